@@ -0,0 +1,404 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry is one line of an audit log: a mutating call and its outcome.
+// Hash covers every other field including Prev, the Hash of the entry
+// before it, so editing, removing or reordering entries breaks the chain.
+//
+// The hash is unkeyed, so the chain only detects accidental or careless
+// edits. Anyone who can write the file can recompute every hash after an
+// edit, and removing entries from the end leaves a valid chain. Copy the
+// Hash of the last entry somewhere the writers cannot change to detect
+// either.
+type AuditEntry struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Operation string    `json:"operation"`
+	// Feed is the feed URI the call targets; batch calls over several feeds
+	// list them separated by commas.
+	Feed string `json:"feed,omitempty"`
+	// BodySHA256 is the hex SHA-256 digest of the request body.
+	BodySHA256 string `json:"bodySha256,omitempty"`
+	// Status is the response status, 0 when no response was received.
+	Status int `json:"status"`
+	// Error is the Gyoka error code of a non-200 response, or the transport
+	// error.
+	Error string            `json:"error,omitempty"`
+	Items []BatchItemResult `json:"items,omitempty"`
+	Prev  string            `json:"prev"`
+	Hash  string            `json:"hash"`
+}
+
+// computeHash returns the hash of e with its Hash field ignored.
+func (e AuditEntry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditOptions configures OpenAuditLog.
+type AuditOptions struct {
+	// Actor is recorded as who made the calls. Defaults to the name of the
+	// current OS user.
+	Actor string
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// AuditLog appends hash-chained AuditEntry lines to a JSONL file. It is
+// safe for concurrent use. On Unix each append holds an flock on the file
+// and first reads the entries other processes appended, so several
+// processes can share one log without forking the chain. Elsewhere only one
+// process should write a file at a time.
+type AuditLog struct {
+	opts AuditOptions
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64 // bytes of the file read into seq and last
+	seq  int64
+	last string
+}
+
+// OpenAuditLog opens the audit log at path for appending, creating it if
+// needed. The chain continues from the last entry of an existing file; use
+// VerifyAuditLog to check the entries before it. A last line without its
+// newline, as left by a crash during a write, is removed if it is not a
+// complete entry.
+func OpenAuditLog(path string, opts AuditOptions) (*AuditLog, error) {
+	if opts.Actor == "" {
+		if u, err := user.Current(); err == nil {
+			opts.Actor = u.Username
+		}
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &AuditLog{opts: opts, f: f}
+	err = l.locked(func() error {
+		if err := repairAuditTail(f); err != nil {
+			return err
+		}
+		return l.catchUp()
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// locked runs fn holding the file lock.
+func (l *AuditLog) locked(fn func() error) error {
+	if err := lockFile(l.f); err != nil {
+		return err
+	}
+	defer unlockFile(l.f)
+	return fn()
+}
+
+// catchUp reads the entries appended since the last call, by this or
+// another process, so the next entry chains onto the last one in the file.
+func (l *AuditLog) catchUp() error {
+	fi, err := l.f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() < l.size {
+		return fmt.Errorf("%s shrank from %d to %d bytes", l.f.Name(), l.size, fi.Size())
+	}
+	err = scanAuditLog(io.NewSectionReader(l.f, l.size, fi.Size()-l.size), func(_ int, e AuditEntry) error {
+		l.seq, l.last = e.Seq, e.Hash
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	l.size = fi.Size()
+	return nil
+}
+
+// repairAuditTail ends a last line that lacks its newline. The line is kept
+// if it holds a complete entry and truncated otherwise.
+func repairAuditTail(f *os.File) error {
+	fi, err := f.Stat()
+	if err != nil || fi.Size() == 0 {
+		return err
+	}
+	size := fi.Size()
+	buf := make([]byte, 4096)
+	tail := int64(0)
+	for end := size; end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return err
+		}
+		if end == size && chunk[len(chunk)-1] == '\n' {
+			return nil
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			tail = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+	line := make([]byte, size-tail)
+	if _, err := f.ReadAt(line, tail); err != nil {
+		return err
+	}
+	var e AuditEntry
+	if json.Unmarshal(line, &e) == nil {
+		_, err := f.Write([]byte{'\n'})
+		return err
+	}
+	return f.Truncate(tail)
+}
+
+// Close closes the log file.
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// append chains e to the log and writes it as one line.
+func (l *AuditLog) append(e AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.locked(func() error {
+		if err := l.catchUp(); err != nil {
+			return err
+		}
+		e.Seq, e.Prev = l.seq+1, l.last
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		e.Hash = hash
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		n, err := l.f.Write(append(line, '\n'))
+		l.size += int64(n)
+		if err != nil {
+			return err
+		}
+		l.seq, l.last = e.Seq, e.Hash
+		return nil
+	})
+}
+
+// WithAuditLog wraps the client's HttpRequestDoer so every mutating call,
+// i.e. any method but GET and HEAD, is appended to log with its response
+// status and, for batch calls, the per-item results. Calls that fail
+// without a response are logged too. If an entry cannot be written the
+// call returns the write error, even though the server applied it. It must
+// come after WithHTTPClient.
+func WithAuditLog(log *AuditLog) ClientOption {
+	return func(c *Client) error {
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet || req.Method == http.MethodHead {
+					return next.Do(req)
+				}
+				return log.do(req, next)
+			})
+		})
+		return nil
+	}
+}
+
+func (l *AuditLog) do(req *http.Request, next HttpRequestDoer) (*http.Response, error) {
+	reqInfo := inspectRequest(req)
+	e := AuditEntry{Time: l.opts.Now().UTC(), Actor: l.opts.Actor, Operation: reqInfo.Operation, Feed: reqInfo.Feed}
+	if reqInfo.Body != nil {
+		sum := sha256.Sum256(reqInfo.Body)
+		e.BodySHA256 = hex.EncodeToString(sum[:])
+	}
+
+	rsp, err := next.Do(req)
+	if err != nil {
+		e.Error = err.Error()
+		if werr := l.append(e); werr != nil {
+			return nil, errors.Join(err, fmt.Errorf("gyoka: audit log: %w", werr))
+		}
+		return rsp, err
+	}
+	rspInfo := inspectResponseHead(e.Operation, rsp)
+	e.Status, e.Error = rspInfo.StatusCode, rspInfo.ErrorCode
+	e.Items = auditItems(e.Operation, rsp, rspInfo.Body)
+	if err := l.append(e); err != nil {
+		rsp.Body.Close()
+		return nil, fmt.Errorf("gyoka: audit log: %w", err)
+	}
+	return rsp, nil
+}
+
+// auditItems returns the per-post results of a successful batch call.
+func auditItems(op string, rsp *http.Response, body []byte) []BatchItemResult {
+	if rsp.StatusCode != http.StatusOK {
+		return nil
+	}
+	copied := &http.Response{StatusCode: rsp.StatusCode, Header: rsp.Header, Body: io.NopCloser(bytes.NewReader(body))}
+	switch op {
+	case OpPostBatchAddPosts:
+		if parsed, err := ParsePostBatchAddPostsResponse(copied); err == nil {
+			return parsed.Items()
+		}
+	case OpPostBatchRemovePosts:
+		if parsed, err := ParsePostBatchRemovePostsResponse(copied); err == nil {
+			return parsed.Items()
+		}
+	}
+	return nil
+}
+
+// readAuditLog calls fn for every entry of the log at path with its line
+// number.
+func readAuditLog(path string, fn func(line int, e AuditEntry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return scanAuditLog(f, fn)
+}
+
+// scanAuditLog calls fn for every entry read from r with its line number.
+func scanAuditLog(r io.Reader, fn func(line int, e AuditEntry) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 64<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return &AuditChainError{Line: line, Reason: fmt.Sprintf("invalid entry: %v", err)}
+		}
+		if err := fn(line, e); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// AuditChainError reports where an audit log fails verification.
+type AuditChainError struct {
+	Line int
+	// Seq is the sequence number the entry claims, 0 if it did not parse.
+	Seq    int64
+	Reason string
+}
+
+func (e *AuditChainError) Error() string {
+	return fmt.Sprintf("audit log line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// VerifyAuditLog checks the hash chain of the audit log at path and
+// returns the number of entries that verified. A broken chain is returned as
+// *AuditChainError naming the first entry that does not verify.
+func VerifyAuditLog(path string) (int, error) {
+	n := 0
+	var prev AuditEntry
+	err := readAuditLog(path, func(line int, e AuditEntry) error {
+		fail := func(reason string) error {
+			return &AuditChainError{Line: line, Seq: e.Seq, Reason: reason}
+		}
+		hash, err := e.computeHash()
+		switch {
+		case err != nil:
+			return fail(err.Error())
+		case hash != e.Hash:
+			return fail("hash does not match the entry")
+		case e.Prev != prev.Hash:
+			return fail("prev does not match the hash of the entry before")
+		case e.Seq != prev.Seq+1:
+			return fail(fmt.Sprintf("seq follows %d", prev.Seq))
+		}
+		prev = e
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// AuditQuery selects audit entries. Zero fields match everything.
+type AuditQuery struct {
+	Actor     string
+	Operation string
+	// Feed matches entries whose Feed is or contains this URI.
+	Feed         string
+	Since, Until time.Time
+	// FailedOnly matches entries with an error or a failed item.
+	FailedOnly bool
+}
+
+// Match reports whether e is selected by q.
+func (q AuditQuery) Match(e AuditEntry) bool {
+	switch {
+	case q.Actor != "" && e.Actor != q.Actor,
+		q.Operation != "" && e.Operation != q.Operation,
+		q.Feed != "" && !feedListContains(e.Feed, q.Feed),
+		!q.Since.IsZero() && e.Time.Before(q.Since),
+		!q.Until.IsZero() && !e.Time.Before(q.Until):
+		return false
+	}
+	if !q.FailedOnly {
+		return true
+	}
+	if e.Error != "" || e.Status != http.StatusOK {
+		return true
+	}
+	for _, item := range e.Items {
+		if !item.OK() {
+			return true
+		}
+	}
+	return false
+}
+
+func feedListContains(list, feed string) bool {
+	return slices.Contains(strings.Split(list, ","), feed)
+}
+
+// QueryAuditLog returns the entries of the audit log at path matched by q,
+// oldest first. It does not verify the chain.
+func QueryAuditLog(path string, q AuditQuery) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	err := readAuditLog(path, func(_ int, e AuditEntry) error {
+		if q.Match(e) {
+			entries = append(entries, e)
+		}
+		return nil
+	})
+	return entries, err
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package client
+
+import "os"
+
+// Audit logs are not locked outside Unix; see AuditLog.
+
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -0,0 +1,143 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+// writeAuditLog registers n feeds through clients that use logs in turn.
+func writeAuditLog(t *testing.T, n int, logs ...*client.AuditLog) {
+	t.Helper()
+	ms := server.NewMemoryServer()
+	clients := make([]*client.ClientWithResponses, len(logs))
+	for i, log := range logs {
+		clients[i] = newTestClient(t, ms.Handler(), client.WithAuditLog(log))
+	}
+	for i := range n {
+		registerFeed(t, clients[i%len(clients)], fmt.Sprintf("%s-%d", testFeed, i))
+	}
+}
+
+func TestAuditLogVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := client.OpenAuditLog(path, client.AuditOptions{Actor: "tester"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newMemoryClient(t, client.WithAuditLog(log))
+	if _, err := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{}).AddPosts(context.Background(), c, testFeed, testPosts(3)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetGetPostsWithResponse(context.Background(), &client.GetGetPostsParams{Feed: testFeed}); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// newMemoryClient's registerFeed and the batch are logged; getPosts is not.
+	if n, err := client.VerifyAuditLog(path); err != nil || n != 2 {
+		t.Fatalf("VerifyAuditLog = %d, %v, want 2 entries", n, err)
+	}
+	entries, err := client.QueryAuditLog(path, client.AuditQuery{Operation: client.OpPostBatchAddPosts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Actor != "tester" || len(entries[0].Items) != 3 {
+		t.Errorf("got batch entries %+v, want one by tester with 3 items", entries)
+	}
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		line   int
+	}{
+		{"edited", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"actor":"tester"`, `"actor":"someone"`, 1)
+			return lines
+		}, 2},
+		{"removed", func(lines []string) []string { return append(lines[:1], lines[2:]...) }, 2},
+		{"reordered", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			log, err := client.OpenAuditLog(path, client.AuditOptions{Actor: "tester"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeAuditLog(t, 4, log)
+			log.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+			if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.VerifyAuditLog(path)
+			var chainErr *client.AuditChainError
+			if !errors.As(err, &chainErr) || chainErr.Line != tt.line {
+				t.Errorf("VerifyAuditLog error = %v, want a chain error at line %d", err, tt.line)
+			}
+		})
+	}
+}
+
+func TestAuditLogSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	var logs []*client.AuditLog
+	for range 2 {
+		log, err := client.OpenAuditLog(path, client.AuditOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { log.Close() })
+		logs = append(logs, log)
+	}
+	writeAuditLog(t, 6, logs...)
+	if n, err := client.VerifyAuditLog(path); err != nil || n != 6 {
+		t.Errorf("VerifyAuditLog = %d, %v, want 6 entries in one chain", n, err)
+	}
+}
+
+func TestAuditLogRepairsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := client.OpenAuditLog(path, client.AuditOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAuditLog(t, 2, log)
+	log.Close()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":3,"time":"2026-`)
+	f.Close()
+
+	log, err = client.OpenAuditLog(path, client.AuditOptions{})
+	if err != nil {
+		t.Fatalf("OpenAuditLog after a torn write: %v", err)
+	}
+	writeAuditLog(t, 1, log)
+	log.Close()
+	if n, err := client.VerifyAuditLog(path); err != nil || n != 3 {
+		t.Errorf("VerifyAuditLog = %d, %v, want 3 entries", n, err)
+	}
+}
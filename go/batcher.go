@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AdaptiveBatchOptions configures NewAdaptiveBatcher. Zero fields take the
+// defaults noted below.
+type AdaptiveBatchOptions struct {
+	// Initial is the starting chunk size. Defaults to 100.
+	Initial int
+	// Min and Max bound the chunk size. They default to 1 and 1000.
+	Min, Max int
+	// Step is added to the size after every healthy full chunk. Defaults to
+	// 10.
+	Step int
+	// Decrease multiplies the size after a failed or slow chunk. Defaults to
+	// 0.5.
+	Decrease float64
+	// TargetLatency is the latency up to which a chunk counts as healthy.
+	// Chunks slower than twice this shrink the size. Defaults to 2s.
+	TargetLatency time.Duration
+	// MaxConsecutiveFailures stops a call after this many new chunks failed
+	// without a successful request in between, so an outage is reported
+	// instead of bisected. Failures of bisected halves do not count, and
+	// only a successful request resets the count. Defaults to 8.
+	MaxConsecutiveFailures int
+}
+
+// AdaptiveBatcher sends posts with batchAddPosts and batchRemovePosts in
+// chunks whose size it tunes from the responses: it grows while chunks
+// succeed within TargetLatency and shrinks on 413, 500, 504, timeouts and
+// slow chunks. A chunk that fails as a whole is bisected until the posts
+// that cause the failure are isolated; those are reported as failed items.
+//
+// Sizes are tracked per operation and shared by all calls, so one batcher
+// should be used per Gyoka instance.
+type AdaptiveBatcher struct {
+	opts AdaptiveBatchOptions
+
+	mu    sync.Mutex
+	sizes map[string]int
+}
+
+// NewAdaptiveBatcher creates an AdaptiveBatcher.
+func NewAdaptiveBatcher(opts AdaptiveBatchOptions) *AdaptiveBatcher {
+	if opts.Min <= 0 {
+		opts.Min = 1
+	}
+	if opts.Max <= 0 {
+		opts.Max = 1000
+	}
+	if opts.Initial <= 0 {
+		opts.Initial = defaultBatchSize
+	}
+	opts.Max = max(opts.Max, opts.Min)
+	opts.Initial = min(max(opts.Initial, opts.Min), opts.Max)
+	if opts.Step <= 0 {
+		opts.Step = 10
+	}
+	if opts.Decrease <= 0 || opts.Decrease >= 1 {
+		opts.Decrease = 0.5
+	}
+	if opts.TargetLatency <= 0 {
+		opts.TargetLatency = 2 * time.Second
+	}
+	if opts.MaxConsecutiveFailures <= 0 {
+		opts.MaxConsecutiveFailures = 8
+	}
+	return &AdaptiveBatcher{opts: opts, sizes: make(map[string]int)}
+}
+
+// batcherOr returns b, or a batcher that keeps chunks at size posts when b
+// is nil. A size that is not positive means the default size.
+func batcherOr(b *AdaptiveBatcher, size int) *AdaptiveBatcher {
+	if b != nil {
+		return b
+	}
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	return NewAdaptiveBatcher(AdaptiveBatchOptions{Initial: size, Min: size, Max: size})
+}
+
+// Size returns the current chunk size for op, OpPostBatchAddPosts or
+// OpPostBatchRemovePosts.
+func (b *AdaptiveBatcher) Size(op string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size(op)
+}
+
+// Sizes returns the current chunk size of every operation used so far.
+func (b *AdaptiveBatcher) Sizes() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return maps.Clone(b.sizes)
+}
+
+// size returns the size for op. The caller holds b.mu.
+func (b *AdaptiveBatcher) size(op string) int {
+	if n, ok := b.sizes[op]; ok {
+		return n
+	}
+	return b.opts.Initial
+}
+
+func (b *AdaptiveBatcher) grow(op string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sizes[op] = min(b.size(op)+b.opts.Step, b.opts.Max)
+}
+
+func (b *AdaptiveBatcher) shrink(op string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sizes[op] = max(int(float64(b.size(op))*b.opts.Decrease), b.opts.Min)
+}
+
+// AddPosts adds posts to feed and returns the per-post results. Posts
+// isolated by bisection are reported with status "error". The results of
+// the chunks sent before an error are returned with it.
+func (b *AdaptiveBatcher) AddPosts(ctx context.Context, c ClientWithResponsesInterface, feed string, posts []Post) ([]BatchItemResult, error) {
+	return b.send(ctx, OpPostBatchAddPosts, feed, posts, func(chunk []Post) ([]BatchItemResult, error) {
+		resp, err := PostBatchAddPostsPooled(ctx, c, NewBatchAddPostsBody(feed, chunk))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+			return nil, newAPIError(OpPostBatchAddPosts, resp.HTTPResponse, resp.Body)
+		}
+		return resp.Items(), nil
+	})
+}
+
+// RemovePosts removes posts from feed like AddPosts adds them.
+func (b *AdaptiveBatcher) RemovePosts(ctx context.Context, c ClientWithResponsesInterface, feed string, posts []Post) ([]BatchItemResult, error) {
+	return b.send(ctx, OpPostBatchRemovePosts, feed, posts, func(chunk []Post) ([]BatchItemResult, error) {
+		resp, err := PostBatchRemovePostsPooled(ctx, c, NewBatchRemovePostsBody(feed, chunk))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+			return nil, newAPIError(OpPostBatchRemovePosts, resp.HTTPResponse, resp.Body)
+		}
+		return resp.Items(), nil
+	})
+}
+
+func (b *AdaptiveBatcher) send(ctx context.Context, op, feed string, posts []Post, call func([]Post) ([]BatchItemResult, error)) ([]BatchItemResult, error) {
+	var items []BatchItemResult
+	// bisected holds the halves of failed chunks, next one last.
+	var bisected [][]Post
+	failures := 0
+	for len(posts) > 0 || len(bisected) > 0 {
+		var chunk []Post
+		full, fresh := false, false
+		if n := len(bisected); n > 0 {
+			chunk, bisected = bisected[n-1], bisected[:n-1]
+		} else {
+			size := b.Size(op)
+			chunk, posts = posts[:min(size, len(posts))], posts[min(size, len(posts)):]
+			full, fresh = len(chunk) == size, true
+		}
+
+		start := time.Now()
+		result, err := call(chunk)
+		latency := time.Since(start)
+		if err == nil {
+			failures = 0
+			items = append(items, result...)
+			switch {
+			case latency > 2*b.opts.TargetLatency:
+				b.shrink(op)
+			case full && latency <= b.opts.TargetLatency:
+				b.grow(op)
+			}
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return items, err
+		}
+		overload, poison := classifyBatchError(err)
+		if !overload && !poison {
+			return items, err
+		}
+		// Only chunks that were not bisected yet count toward the cap, so
+		// isolating a poison post in a large chunk does not end the call.
+		if fresh {
+			if failures++; failures >= b.opts.MaxConsecutiveFailures {
+				return items, err
+			}
+		}
+		if overload {
+			b.shrink(op)
+		}
+		if len(chunk) == 1 {
+			items = append(items, BatchItemResult{Feed: feed, Uri: chunk[0].Uri, Status: BatchItemError, Error: err.Error()})
+			continue
+		}
+		half := len(chunk) / 2
+		bisected = append(bisected, chunk[half:], chunk[:half])
+	}
+	return items, nil
+}
+
+// classifyBatchError reports whether err is a sign of an overloaded worker,
+// which shrinks the chunk size, and whether it may be caused by some posts
+// of the chunk, which bisects it. Overload errors bisect as well.
+func classifyBatchError(err error) (overload, poison bool) {
+	var te *TimeoutError
+	if errors.As(err, &te) || errors.Is(err, context.DeadlineExceeded) {
+		return true, true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false, false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusGatewayTimeout:
+		return true, true
+	case http.StatusBadRequest:
+		return false, true
+	}
+	return false, false
+}
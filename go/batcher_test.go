@@ -0,0 +1,86 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestAdaptiveBatcherOutage(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, `{"error":"InternalServerError","message":"down"}`)
+	}))
+	b := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{Initial: 16})
+
+	items, err := b.AddPosts(context.Background(), c, testFeed, testPosts(64))
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("AddPosts error = %v, want the 500 APIError", err)
+	}
+	// One bisected chunk of 16 takes 31 requests; the cap stops the call
+	// after 7 more chunks.
+	if n := requests.Load(); n > 40 {
+		t.Errorf("sent %d requests during an outage, want at most 40", n)
+	}
+	if len(items) >= 64 {
+		t.Errorf("got %d item results, want the call to stop early", len(items))
+	}
+}
+
+func TestAdaptiveBatcherIsolatesPoison(t *testing.T) {
+	ms := server.NewMemoryServer()
+	poison := map[string]bool{testPostURI(0): true, testPostURI(1): true, testPostURI(150): true, testPostURI(299): true}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		for uri := range poison {
+			if strings.Contains(string(body), uri) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"error":"BadRequest","message":"poison"}`)
+				return
+			}
+		}
+		ms.Handler().ServeHTTP(w, r)
+	}))
+	registerFeed(t, c, testFeed)
+	b := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{Initial: 100})
+
+	items, err := b.AddPosts(context.Background(), c, testFeed, testPosts(300))
+	if err != nil {
+		t.Fatalf("AddPosts: %v", err)
+	}
+	ok := 0
+	for _, item := range items {
+		switch {
+		case item.OK():
+			ok++
+		case !poison[item.Uri]:
+			t.Errorf("item %s failed: %s", item.Uri, item.Error)
+		}
+	}
+	if ok != 300-len(poison) || len(items) != 300 {
+		t.Errorf("got %d results with %d ok, want 300 with %d ok", len(items), ok, 300-len(poison))
+	}
+	stored := feedURIs(t, c, testFeed)
+	if len(stored) != 300-len(poison) {
+		t.Errorf("feed holds %d posts, want %d", len(stored), 300-len(poison))
+	}
+	for uri := range poison {
+		if stored[uri] {
+			t.Errorf("poison post %s was stored", uri)
+		}
+	}
+}
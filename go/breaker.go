@@ -0,0 +1,302 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets calls through and records their outcome.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails calls with a CircuitOpenError until OpenTimeout has
+	// passed.
+	BreakerOpen
+	// BreakerHalfOpen lets HalfOpenProbes calls through; the breaker closes
+	// when they all succeed and opens again on the first failure.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// Call classes tracked by separate breakers.
+const (
+	BreakerRead  = "read"
+	BreakerWrite = "write"
+)
+
+// BreakerChange describes a state transition of one breaker.
+type BreakerChange struct {
+	// Host is the Gyoka instance, e.g. "gyoka.example.workers.dev".
+	Host string
+	// Class is BreakerRead or BreakerWrite.
+	Class    string
+	From, To BreakerState
+}
+
+// CircuitOpenError is returned without sending the request while a breaker
+// is open.
+type CircuitOpenError struct {
+	Host      string
+	Class     string
+	Operation string
+	// RetryAfter is the time until the breaker lets a probe through.
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("gyoka: %s: circuit open for %s %s, retry in %s", e.Operation, e.Host, e.Class, e.RetryAfter.Round(time.Millisecond))
+}
+
+// BreakerOptions configures WithCircuitBreaker. Zero fields take the
+// defaults noted below.
+type BreakerOptions struct {
+	// Window is the number of recent calls the rates are computed over.
+	// Defaults to 20.
+	Window int
+	// MinCalls is the number of calls in the window before the breaker can
+	// trip. Defaults to 10.
+	MinCalls int
+	// ErrorRate trips the breaker when this share of the window failed.
+	// Defaults to 0.5.
+	ErrorRate float64
+	// SlowCall is the latency from which a call counts as slow. Defaults to
+	// 5s.
+	SlowCall time.Duration
+	// SlowRate trips the breaker when this share of the window was slow.
+	// Defaults to 0.8.
+	SlowRate float64
+	// OpenTimeout is how long the breaker stays open. Defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of calls let through while half-open.
+	// Defaults to 1.
+	HalfOpenProbes int
+	// IsFailure reports whether a call counts as failed. The default counts
+	// transport errors, 429 and 5xx responses. Calls whose context was
+	// cancelled are never counted; calls that ran past their deadline always
+	// count as failed and slow.
+	IsFailure func(rsp *http.Response, err error) bool
+	// OnStateChange is called after every transition, outside the breaker's
+	// lock.
+	OnStateChange func(BreakerChange)
+}
+
+// WithCircuitBreaker wraps the client's HttpRequestDoer with circuit
+// breakers, one per host for reads (GET) and one for writes. It must come
+// after WithHTTPClient.
+func WithCircuitBreaker(opts BreakerOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.Window <= 0 {
+			opts.Window = 20
+		}
+		if opts.MinCalls <= 0 {
+			opts.MinCalls = 10
+		}
+		opts.MinCalls = min(opts.MinCalls, opts.Window)
+		if opts.ErrorRate <= 0 {
+			opts.ErrorRate = 0.5
+		}
+		if opts.SlowCall <= 0 {
+			opts.SlowCall = 5 * time.Second
+		}
+		if opts.SlowRate <= 0 {
+			opts.SlowRate = 0.8
+		}
+		if opts.OpenTimeout <= 0 {
+			opts.OpenTimeout = 30 * time.Second
+		}
+		if opts.HalfOpenProbes <= 0 {
+			opts.HalfOpenProbes = 1
+		}
+		if opts.IsFailure == nil {
+			opts.IsFailure = defaultIsFailure
+		}
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return &breakerDoer{next: next, opts: opts, breakers: make(map[breakerKey]*breaker)}
+		})
+		return nil
+	}
+}
+
+func defaultIsFailure(rsp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return rsp.StatusCode == http.StatusTooManyRequests || rsp.StatusCode >= 500
+}
+
+type breakerKey struct {
+	host, class string
+}
+
+type breakerDoer struct {
+	next HttpRequestDoer
+	opts BreakerOptions
+
+	mu       sync.Mutex
+	breakers map[breakerKey]*breaker
+}
+
+func (d *breakerDoer) Do(req *http.Request) (*http.Response, error) {
+	key := breakerKey{host: req.URL.Host, class: BreakerWrite}
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		key.class = BreakerRead
+	}
+	d.mu.Lock()
+	b := d.breakers[key]
+	if b == nil {
+		b = &breaker{opts: &d.opts, key: key, outcomes: make([]outcome, 0, d.opts.Window)}
+		d.breakers[key] = b
+	}
+	d.mu.Unlock()
+
+	probe, wait, change := b.allow(time.Now())
+	d.notify(change)
+	if wait > 0 {
+		return nil, &CircuitOpenError{Host: key.host, Class: key.class, Operation: operationName(req), RetryAfter: wait}
+	}
+
+	start := time.Now()
+	rsp, err := d.next.Do(req)
+	if errors.Is(err, context.Canceled) || errors.Is(req.Context().Err(), context.Canceled) {
+		b.cancel(probe)
+		return rsp, err
+	}
+	o := outcome{failed: d.opts.IsFailure(rsp, err), slow: time.Since(start) >= d.opts.SlowCall}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		o = outcome{failed: true, slow: true}
+	}
+	d.notify(b.record(o, probe, time.Now()))
+	return rsp, err
+}
+
+func (d *breakerDoer) notify(change *BreakerChange) {
+	if change != nil && d.opts.OnStateChange != nil {
+		d.opts.OnStateChange(*change)
+	}
+}
+
+type outcome struct {
+	failed, slow bool
+}
+
+type breaker struct {
+	opts *BreakerOptions
+	key  breakerKey
+
+	mu       sync.Mutex
+	state    BreakerState
+	openedAt time.Time
+	// outcomes is a ring of the last Window calls while closed.
+	outcomes []outcome
+	pos      int
+	// probes counts calls in flight and successes while half-open.
+	probes, successes int
+}
+
+// allow reports whether a call may proceed. A positive wait means the call
+// must fail fast; probe marks calls let through while half-open.
+func (b *breaker) allow(now time.Time) (probe bool, wait time.Duration, change *BreakerChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen {
+		if wait := b.openedAt.Add(b.opts.OpenTimeout).Sub(now); wait > 0 {
+			return false, wait, nil
+		}
+		change = b.transition(BreakerHalfOpen, now)
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probes+b.successes >= b.opts.HalfOpenProbes {
+			// Other probes are deciding; retry once they are done.
+			return false, time.Millisecond, change
+		}
+		b.probes++
+		return true, 0, change
+	}
+	return false, 0, change
+}
+
+// cancel forgets a call whose context was cancelled.
+func (b *breaker) cancel(probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probes--
+	}
+}
+
+func (b *breaker) record(o outcome, probe bool, now time.Time) *BreakerChange {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerHalfOpen:
+		if !probe {
+			return nil
+		}
+		b.probes--
+		if o.failed || o.slow {
+			return b.transition(BreakerOpen, now)
+		}
+		b.successes++
+		if b.successes >= b.opts.HalfOpenProbes {
+			return b.transition(BreakerClosed, now)
+		}
+	case BreakerClosed:
+		if len(b.outcomes) < b.opts.Window {
+			b.outcomes = append(b.outcomes, o)
+		} else {
+			b.outcomes[b.pos] = o
+			b.pos = (b.pos + 1) % b.opts.Window
+		}
+		if len(b.outcomes) < b.opts.MinCalls {
+			return nil
+		}
+		var failed, slow int
+		for _, o := range b.outcomes {
+			if o.failed {
+				failed++
+			}
+			if o.slow {
+				slow++
+			}
+		}
+		n := float64(len(b.outcomes))
+		if float64(failed)/n >= b.opts.ErrorRate || float64(slow)/n >= b.opts.SlowRate {
+			return b.transition(BreakerOpen, now)
+		}
+	}
+	return nil
+}
+
+// transition moves to state and resets the counters of the new state.
+func (b *breaker) transition(state BreakerState, now time.Time) *BreakerChange {
+	change := &BreakerChange{Host: b.key.host, Class: b.key.class, From: b.state, To: state}
+	b.state = state
+	b.probes, b.successes = 0, 0
+	switch state {
+	case BreakerOpen:
+		b.openedAt = now
+	case BreakerClosed:
+		b.outcomes, b.pos = b.outcomes[:0], 0
+	}
+	return change
+}
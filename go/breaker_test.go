@@ -0,0 +1,115 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	ms := server.NewMemoryServer()
+	var failing atomic.Bool
+	var hits atomic.Int32
+	var mu sync.Mutex
+	var changes []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failing.Load() && r.Method == http.MethodGet {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		ms.Handler().ServeHTTP(w, r)
+	}), client.WithCircuitBreaker(client.BreakerOptions{
+		Window:      4,
+		MinCalls:    4,
+		OpenTimeout: 50 * time.Millisecond,
+		OnStateChange: func(c client.BreakerChange) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, c.Class+" "+c.From.String()+"->"+c.To.String())
+		},
+	}))
+	ctx := context.Background()
+	listFeeds := func() error {
+		rsp, err := c.GetListFeedsWithResponse(ctx)
+		if err == nil && rsp.StatusCode() != http.StatusOK {
+			err = errors.New(rsp.Status())
+		}
+		return err
+	}
+
+	failing.Store(true)
+	for range 4 {
+		if err := listFeeds(); err == nil {
+			t.Fatal("listFeeds succeeded against a failing server")
+		}
+	}
+	var open *client.CircuitOpenError
+	before := hits.Load()
+	if err := listFeeds(); !errors.As(err, &open) || open.Class != client.BreakerRead {
+		t.Fatalf("listFeeds error = %v, want a read CircuitOpenError", err)
+	}
+	if hits.Load() != before {
+		t.Error("an open breaker sent the request")
+	}
+	// Writes have their own breaker.
+	registerFeed(t, c, testFeed)
+
+	time.Sleep(60 * time.Millisecond)
+	if err := listFeeds(); err == nil || errors.As(err, &open) {
+		t.Fatalf("half-open probe error = %v, want the server's failure", err)
+	}
+	if err := listFeeds(); !errors.As(err, &open) {
+		t.Fatalf("listFeeds error = %v after a failed probe, want CircuitOpenError", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+	for range 2 {
+		if err := listFeeds(); err != nil {
+			t.Fatalf("listFeeds after recovery: %v", err)
+		}
+	}
+
+	want := []string{
+		"read closed->open",
+		"read open->half-open",
+		"read half-open->open",
+		"read open->half-open",
+		"read half-open->closed",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(changes, want) {
+		t.Errorf("transitions = %q, want %q", changes, want)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledCalls(t *testing.T) {
+	var changes atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}), client.WithCircuitBreaker(client.BreakerOptions{
+		Window:        2,
+		MinCalls:      2,
+		OnStateChange: func(client.BreakerChange) { changes.Add(1) },
+	}))
+	for range 4 {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(5*time.Millisecond, cancel)
+		if _, err := c.GetListFeedsWithResponse(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("listFeeds error = %v, want context.Canceled", err)
+		}
+	}
+	if n := changes.Load(); n != 0 {
+		t.Errorf("cancelled calls caused %d transitions", n)
+	}
+}
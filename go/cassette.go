@@ -0,0 +1,321 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a Cassette records or replays.
+type CassetteMode string
+
+const (
+	// CassetteRecord forwards requests and writes every interaction to the
+	// cassette file on Close.
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay serves responses from the cassette file.
+	CassetteReplay CassetteMode = "replay"
+)
+
+// CassetteMatch selects how replayed requests find their interaction.
+type CassetteMatch string
+
+const (
+	// MatchInOrder serves interactions in recorded order. A request whose
+	// method or path differs from the next interaction is unmatched.
+	MatchInOrder CassetteMatch = "order"
+	// MatchByRequest serves the first unused interaction with the same
+	// method, path, query and normalised JSON body.
+	MatchByRequest CassetteMatch = "request"
+)
+
+// CassetteHeaders are the request and response headers a Cassette records.
+// All others are dropped, so credentials such as Authorization, Cookie,
+// Set-Cookie and the RedactedHeaders never reach the cassette file.
+var CassetteHeaders = []string{"Accept", "Content-Type", "Content-Encoding", "Retry-After"}
+
+// ErrCassetteUnmatched is returned in strict replay for requests without a
+// matching interaction.
+var ErrCassetteUnmatched = errors.New("cassette: no matching interaction")
+
+// CassetteOptions configures NewCassette.
+type CassetteOptions struct {
+	Path string
+	Mode CassetteMode
+	// Next sends requests in record mode and, without Strict, unmatched
+	// requests in replay mode. Defaults to http.DefaultClient.
+	Next HttpRequestDoer
+	// RedactDIDs replaces DIDs in URLs and bodies with stable placeholders
+	// of the form did:redacted:<hash>. Replayed responses carry the
+	// placeholders.
+	RedactDIDs bool
+	// Match defaults to MatchByRequest.
+	Match CassetteMatch
+	// Strict makes replay fail with ErrCassetteUnmatched instead of
+	// forwarding unmatched requests to Next.
+	Strict bool
+	// Headers lists headers to record in addition to CassetteHeaders.
+	// Headers in RedactedHeaders are dropped even when listed.
+	Headers []string
+}
+
+// Interaction is one recorded request and response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a cassette, with only the
+// recorded headers. Query is sorted and Body is normalised JSON.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is an HttpRequestDoer that records Gyoka calls to a file or
+// replays them, so code built on ClientWithResponses can be tested without a
+// live worker. Only CassetteHeaders and CassetteOptions.Headers are written.
+// A recording cassette must be closed to write its file.
+//
+//	cas, err := client.NewCassette(client.CassetteOptions{Path: "testdata/trim.json", Mode: client.CassetteReplay, Strict: true})
+//	c, err := client.NewClientWithResponses("http://gyoka.test", client.WithHTTPClient(cas))
+type Cassette struct {
+	opts CassetteOptions
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	next         int
+	// headers is the set of canonical header names to record.
+	headers map[string]bool
+}
+
+// NewCassette opens a cassette. Replay mode loads opts.Path; record mode
+// starts an empty cassette that overwrites opts.Path on Close.
+func NewCassette(opts CassetteOptions) (*Cassette, error) {
+	if opts.Path == "" {
+		return nil, errors.New("cassette: path is required")
+	}
+	if opts.Next == nil {
+		opts.Next = http.DefaultClient
+	}
+	if opts.Match == "" {
+		opts.Match = MatchByRequest
+	}
+	c := &Cassette{opts: opts, headers: make(map[string]bool)}
+	for _, h := range slices.Concat(CassetteHeaders, opts.Headers) {
+		c.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, h := range RedactedHeaders {
+		delete(c.headers, http.CanonicalHeaderKey(h))
+	}
+	switch opts.Mode {
+	case CassetteRecord:
+	case CassetteReplay:
+		data, err := os.ReadFile(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", opts.Path, err)
+		}
+		c.used = make([]bool, len(c.interactions))
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %q", opts.Mode)
+	}
+	return c, nil
+}
+
+func (c *Cassette) Do(req *http.Request) (*http.Response, error) {
+	if c.opts.Mode == CassetteRecord {
+		return c.record(req)
+	}
+	return c.replay(req)
+}
+
+// Unused returns the recorded interactions that were not replayed.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []Interaction
+	for i, u := range c.used {
+		if !u {
+			unused = append(unused, c.interactions[i])
+		}
+	}
+	return unused
+}
+
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	recorded := c.recordRequest(req)
+	rsp, err := c.opts.Next.Do(req)
+	if err != nil {
+		return rsp, err
+	}
+	body, err := io.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: rsp.StatusCode,
+			Header:     c.recordHeader(rsp.Header),
+			Body:       c.redactDIDs(string(body)),
+		},
+	})
+	return rsp, nil
+}
+
+// Close writes the recorded interactions to the cassette file. It does
+// nothing in replay mode.
+func (c *Cassette) Close() error {
+	if c.opts.Mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.opts.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.opts.Path)
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	recorded := c.recordRequest(req)
+	c.mu.Lock()
+	i := c.match(recorded)
+	if i >= 0 {
+		c.used[i] = true
+	}
+	c.mu.Unlock()
+	if i < 0 {
+		if c.opts.Strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrCassetteUnmatched, recorded.Method, recorded.Path)
+		}
+		return c.opts.Next.Do(req)
+	}
+	r := c.interactions[i].Response
+	return newResponse(req, r.StatusCode, r.Header.Clone(), r.Body), nil
+}
+
+// match returns the index of the interaction for r, or -1.
+func (c *Cassette) match(r RecordedRequest) int {
+	if c.opts.Match == MatchInOrder {
+		if c.next >= len(c.interactions) {
+			return -1
+		}
+		want := c.interactions[c.next].Request
+		if want.Method != r.Method || want.Path != r.Path {
+			return -1
+		}
+		c.next++
+		return c.next - 1
+	}
+	for i, in := range c.interactions {
+		if c.used[i] {
+			continue
+		}
+		want := in.Request
+		if want.Method == r.Method && want.Path == r.Path && want.Query == r.Query && want.Body == r.Body {
+			return i
+		}
+	}
+	return -1
+}
+
+// recordRequest captures req with only the recorded headers, DIDs redacted
+// if configured and the JSON body normalised.
+func (c *Cassette) recordRequest(req *http.Request) RecordedRequest {
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   c.redactDIDs(req.URL.Path),
+		Query:  c.redactQuery(req.URL.Query()),
+		Header: c.recordHeader(req.Header),
+		Body:   c.redactDIDs(normalizeJSON(peekRequestBody(req))),
+	}
+}
+
+// recordHeader returns the recorded headers of h, or nil if there are none.
+func (c *Cassette) recordHeader(h http.Header) http.Header {
+	var out http.Header
+	for key, vs := range h {
+		if c.headers[http.CanonicalHeaderKey(key)] {
+			if out == nil {
+				out = make(http.Header)
+			}
+			out[http.CanonicalHeaderKey(key)] = slices.Clone(vs)
+		}
+	}
+	return out
+}
+
+// redactQuery encodes q with sorted keys and DIDs redacted in the values.
+func (c *Cassette) redactQuery(q url.Values) string {
+	for _, vs := range q {
+		for i, v := range vs {
+			vs[i] = c.redactDIDs(v)
+		}
+	}
+	return q.Encode()
+}
+
+// normalizeJSON re-encodes a JSON body with sorted keys and no insignificant
+// whitespace. Non-JSON bodies are returned unchanged.
+func normalizeJSON(body []byte) string {
+	var v any
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+var didPattern = regexp.MustCompile(`did:[a-z]+:[A-Za-z0-9._%-]+(?::[A-Za-z0-9._%-]+)*`)
+
+func (c *Cassette) redactDIDs(s string) string {
+	if !c.opts.RedactDIDs {
+		return s
+	}
+	return didPattern.ReplaceAllStringFunc(s, func(did string) string {
+		if strings.HasPrefix(did, "did:redacted:") {
+			return did
+		}
+		sum := sha256.Sum256([]byte(did))
+		return "did:redacted:" + hex.EncodeToString(sum[:6])
+	})
+}
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestCassetteRedactsCredentials(t *testing.T) {
+	ms := server.NewMemoryServer()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "CF_Authorization", Value: "session-secret"})
+		ms.Handler().ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cas, err := client.NewCassette(client.CassetteOptions{Path: path, Mode: client.CassetteRecord, Next: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer bearer-secret")
+		req.Header.Set("Cookie", "CF_Authorization=cookie-secret")
+		req.Header.Set("Proxy-Authorization", "Basic proxy-secret")
+		req.Header.Set("X-API-Key", "api-key-secret")
+		return nil
+	}
+	c, err := client.NewClientWithResponses(srv.URL, client.WithHTTPClient(cas), client.WithRequestEditorFn(secrets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerFeed(t, c, testFeed)
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("cassette file written before Close")
+	}
+	if err := cas.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"bearer-secret", "cookie-secret", "proxy-secret", "api-key-secret", "session-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "application/json") {
+		t.Errorf("cassette lost Content-Type:\n%s", data)
+	}
+
+	replay, err := client.NewCassette(client.CassetteOptions{Path: path, Mode: client.CassetteReplay, Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := client.NewClientWithResponses("http://gyoka.test", client.WithHTTPClient(replay))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerFeed(t, rc, testFeed)
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("%d interactions not replayed", len(unused))
+	}
+}
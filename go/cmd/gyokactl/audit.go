@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// runAudit implements "audit verify" and "audit query".
+func runAudit(_ context.Context, g *globalFlags, args []string) int {
+	if len(args) == 0 || (args[0] != "verify" && args[0] != "query") {
+		fmt.Fprintln(os.Stderr, "usage: gyokactl audit verify|query [-log audit.jsonl] [flags]")
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("audit "+args[0], flag.ContinueOnError)
+	path := fs.String("log", g.auditPath, "audit log file, defaults to -audit-log")
+	var q client.AuditQuery
+	var since, until string
+	asJSON := fs.Bool("json", false, "print matching entries as JSON lines")
+	if args[0] == "query" {
+		fs.StringVar(&q.Actor, "actor", "", "only entries of this actor")
+		fs.StringVar(&q.Operation, "op", "", "only entries of this operation, e.g. postTrimFeed")
+		fs.StringVar(&q.Feed, "feed", "", "only entries for this feed URI")
+		fs.StringVar(&since, "since", "", "only entries at or after this RFC 3339 time or duration ago, e.g. 24h")
+		fs.StringVar(&until, "until", "", "only entries before this RFC 3339 time or duration ago")
+		fs.BoolVar(&q.FailedOnly, "failed", false, "only entries with an error or failed items")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(os.Stderr, "gyokactl audit: -log or -audit-log is required")
+		return exitUsage
+	}
+
+	if args[0] == "verify" {
+		n, err := client.VerifyAuditLog(*path)
+		if err != nil {
+			return errorf("%v", err)
+		}
+		fmt.Printf("ok: %d entries\n", n)
+		return exitOK
+	}
+
+	var err error
+	if q.Since, err = parseTimeFlag(since); err != nil {
+		fmt.Fprintf(os.Stderr, "gyokactl audit: -since: %v\n", err)
+		return exitUsage
+	}
+	if q.Until, err = parseTimeFlag(until); err != nil {
+		fmt.Fprintf(os.Stderr, "gyokactl audit: -until: %v\n", err)
+		return exitUsage
+	}
+	entries, err := client.QueryAuditLog(*path, q)
+	if err != nil {
+		return errorf("%v", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, e := range entries {
+		if *asJSON {
+			_ = enc.Encode(e)
+			continue
+		}
+		status := fmt.Sprint(e.Status)
+		if e.Error != "" {
+			status += " " + e.Error
+		}
+		fmt.Printf("%d %s %s %s %s %s", e.Seq, e.Time.Format(time.RFC3339), e.Actor, e.Operation, e.Feed, status)
+		if len(e.Items) > 0 {
+			failed := 0
+			for _, item := range e.Items {
+				if !item.OK() {
+					failed++
+				}
+			}
+			fmt.Printf(" items=%d failed=%d", len(e.Items), failed)
+		}
+		fmt.Println()
+	}
+	return exitOK
+}
+
+// parseTimeFlag parses an RFC 3339 time or a duration before now. An empty
+// value is the zero time.
+func parseTimeFlag(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// exitUnknown is returned by doctor when the check could not run at all.
+const exitUnknown = 3
+
+// runDoctor checks the instance and its feeds. The exit code is 0 when
+// healthy, 1 on warnings, 2 on critical findings and 3 if the check could
+// not run, i.e. the client could not be created or neither ping nor
+// listFeeds reached the instance, so it can be used directly from cron or a
+// monitoring agent. Invalid flags and -help exit with 3 too, so a typo is
+// not mistaken for a critical finding.
+func runDoctor(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	sample := fs.Int("sample", 50, "number of posts sampled per feed")
+	staleAfter := fs.Duration("stale-after", 24*time.Hour, "flag active feeds whose newest post is older than this")
+	concurrency := fs.Int("concurrency", 4, "number of feeds checked at once")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUnknown
+	}
+
+	cl, err := g.apiClient()
+	if err != nil {
+		errorf("create client: %v", err)
+		return exitUnknown
+	}
+	h := client.NewHealthChecker(cl)
+	h.SampleSize = *sample
+	h.StaleAfter = *staleAfter
+	h.Concurrency = *concurrency
+	report := h.Check(ctx)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return doctorExitCode(report)
+	}
+	if report.Ping.OK {
+		fmt.Printf("ping: ok (%dms)\n", report.Ping.LatencyMs)
+	} else {
+		fmt.Printf("ping: %s\n", report.Ping.Error)
+	}
+	if report.ListFeedsError != "" {
+		fmt.Printf("listFeeds: %s\n", report.ListFeedsError)
+	}
+	for _, f := range report.Feeds {
+		fmt.Printf("%-8s %s (posts sampled: %d)\n", f.Severity(), f.Feed, f.SampledPosts)
+		for _, issue := range f.Issues {
+			fmt.Printf("         - %s: %s\n", issue.Kind, issue.Message)
+		}
+	}
+	fmt.Printf("status: %s\n", report.Status)
+	return doctorExitCode(report)
+}
+
+// doctorExitCode is the report's exit code, or exitUnknown when the
+// instance could not be reached at all.
+func doctorExitCode(report *client.HealthReport) int {
+	if !report.Ping.OK && report.ListFeedsError != "" {
+		return exitUnknown
+	}
+	return report.ExitCode()
+}
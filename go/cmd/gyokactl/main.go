@@ -0,0 +1,220 @@
+// Command gyokactl runs maintenance tasks against a Gyoka editor API.
+//
+//	gyokactl [global flags] <command> [command flags]
+//
+// Authentication headers are read from the environment:
+// GYOKA_API_KEY, CF_ACCESS_CLIENT_ID and CF_ACCESS_CLIENT_SECRET.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// Exit codes shared by all commands.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, g *globalFlags, args []string) int
+}
+
+var commands = []command{
+	{"retention", "apply feed retention policies from a config file", runRetention},
+	{"doctor", "check the instance and report unhealthy feeds", runDoctor},
+	{"migrate", "copy feeds and posts to another Gyoka instance", runMigrate},
+	{"merge", "merge or clone feeds into a target feed", runMerge},
+	{"registry", "plan or apply feed registrations from a manifest", runRegistry},
+	{"remove-author", "remove an author's posts from every feed", runRemoveAuthor},
+	{"audit", "verify or query an audit log", runAudit},
+	{"undo", "list undo journal entries or restore the posts of one", runUndo},
+}
+
+type globalFlags struct {
+	server      string
+	timeout     time.Duration
+	logLevel    string
+	metricsAddr string
+	auditPath   string
+	actor       string
+
+	metrics *client.PrometheusMetrics
+	audit   *client.AuditLog
+}
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	g := &globalFlags{}
+	fs := flag.NewFlagSet("gyokactl", flag.ContinueOnError)
+	fs.StringVar(&g.server, "server", envOr("GYOKA_SERVER", "http://localhost:8787"), "Gyoka editor API base URL (env GYOKA_SERVER)")
+	fs.DurationVar(&g.timeout, "timeout", 0, "timeout for every API call instead of the per-operation defaults; batch calls get 50ms more per post")
+	fs.StringVar(&g.logLevel, "log-level", "", "log API calls to stderr at this level: debug, info, warn or error")
+	fs.StringVar(&g.metricsAddr, "metrics-addr", "", "serve Prometheus metrics of API calls at this address under /metrics")
+	fs.StringVar(&g.auditPath, "audit-log", os.Getenv("GYOKA_AUDIT_LOG"), "append mutating API calls to this hash-chained JSONL file (env GYOKA_AUDIT_LOG)")
+	fs.StringVar(&g.actor, "actor", os.Getenv("GYOKA_ACTOR"), "actor recorded in the audit log, defaults to the OS user (env GYOKA_ACTOR)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gyokactl [flags] <command> [command flags]\n\ncommands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(fs.Output(), "  %-12s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintf(fs.Output(), "\nflags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	name := fs.Arg(0)
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(ctx, g, fs.Args()[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "gyokactl: unknown command %q\n", name)
+	fs.Usage()
+	return exitUsage
+}
+
+// newClient creates a client for server that sends the auth headers found
+// in the environment variables named by env.
+func (g *globalFlags) newClient(server string, env authEnv) (*client.ClientWithResponses, error) {
+	ht := &headerTransport{headers: env.headers()}
+	if g.logLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(g.logLevel)); err != nil {
+			return nil, fmt.Errorf("-log-level: %w", err)
+		}
+		// Log below headerTransport so the auth headers are seen, and redacted.
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		ht.transport = client.NewLoggingTransport(nil, client.LoggingOptions{Logger: logger})
+	}
+	hc := &http.Client{Transport: ht}
+	opts := []client.ClientOption{client.WithHTTPClient(hc), client.WithOperationTimeouts(g.timeouts())}
+	if g.metricsAddr != "" {
+		if g.metrics == nil {
+			g.metrics = client.NewPrometheusMetrics()
+			go func() {
+				if err := g.metrics.ListenAndServe(g.metricsAddr); err != nil {
+					fmt.Fprintf(os.Stderr, "gyokactl: metrics: %v\n", err)
+				}
+			}()
+		}
+		opts = append(opts, client.WithMetrics(g.metrics))
+	}
+	if g.auditPath != "" {
+		if g.audit == nil {
+			audit, err := client.OpenAuditLog(g.auditPath, client.AuditOptions{Actor: g.actor})
+			if err != nil {
+				return nil, fmt.Errorf("-audit-log: %w", err)
+			}
+			g.audit = audit
+		}
+		opts = append(opts, client.WithAuditLog(g.audit))
+	}
+	return client.NewClientWithResponses(server, opts...)
+}
+
+// timeouts returns the operation timeouts for the -timeout flag: the
+// client's per-operation defaults, or the flag's value for every operation.
+func (g *globalFlags) timeouts() client.TimeoutOptions {
+	if g.timeout <= 0 {
+		return client.TimeoutOptions{}
+	}
+	ops := make(map[string]time.Duration, len(client.DefaultOperationTimeouts))
+	for op := range client.DefaultOperationTimeouts {
+		ops[op] = g.timeout
+	}
+	return client.TimeoutOptions{Operations: ops, Default: g.timeout}
+}
+
+// apiClient returns a client for the global -server flag.
+func (g *globalFlags) apiClient() (*client.ClientWithResponses, error) {
+	return g.newClient(g.server, defaultAuthEnv)
+}
+
+// authEnv names the environment variables holding auth header values.
+type authEnv struct {
+	apiKey, accessClientID, accessClientSecret string
+}
+
+var defaultAuthEnv = authEnv{
+	apiKey:             "GYOKA_API_KEY",
+	accessClientID:     "CF_ACCESS_CLIENT_ID",
+	accessClientSecret: "CF_ACCESS_CLIENT_SECRET",
+}
+
+func (e authEnv) headers() map[string]string {
+	h := make(map[string]string)
+	for header, name := range map[string]string{
+		"X-API-Key":               e.apiKey,
+		"CF-Access-Client-Id":     e.accessClientID,
+		"CF-Access-Client-Secret": e.accessClientSecret,
+	} {
+		if v := os.Getenv(name); v != "" {
+			h[header] = v
+		}
+	}
+	return h
+}
+
+type headerTransport struct {
+	headers   map[string]string
+	transport http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	if t.transport == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func errorf(format string, args ...any) int {
+	fmt.Fprintf(os.Stderr, "gyokactl: "+format+"\n", args...)
+	return exitFailure
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+func runMerge(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	target := fs.String("target", "", "feed URI to write the merged posts to")
+	dedup := fs.String("dedup", string(client.DedupByURI), "deduplicate posts by uri or cid")
+	onConflict := fs.String("on-context-conflict", string(client.ContextKeepFirst), "feedContext of duplicates: first, newest or drop")
+	limit := fs.Int("limit", 0, "keep only the newest N merged posts (0 means no cap)")
+	batchSize := fs.Int("batch-size", 0, "posts per batchAddPosts request")
+	adaptive := fs.Bool("adaptive-batch", false, "tune the batch size from server responses, starting at -batch-size")
+	dryRun := fs.Bool("dry-run", false, "only print the preview")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gyokactl merge -target URI [flags] SOURCE_URI...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *target == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	opts := client.MergeOptions{
+		Dedup:             client.DedupMode(*dedup),
+		OnContextConflict: client.ContextConflict(*onConflict),
+		Limit:             *limit,
+		BatchSize:         *batchSize,
+	}
+	if *adaptive {
+		opts.Batcher = client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{Initial: *batchSize})
+	}
+	switch {
+	case opts.Dedup != client.DedupByURI && opts.Dedup != client.DedupByCID:
+		fmt.Fprintf(os.Stderr, "gyokactl merge: unknown dedup mode %q\n", *dedup)
+		return exitUsage
+	case opts.OnContextConflict != client.ContextKeepFirst && opts.OnContextConflict != client.ContextKeepNewest && opts.OnContextConflict != client.ContextDrop:
+		fmt.Fprintf(os.Stderr, "gyokactl merge: unknown context conflict mode %q\n", *onConflict)
+		return exitUsage
+	}
+
+	cl, err := g.apiClient()
+	if err != nil {
+		return errorf("create client: %v", err)
+	}
+	var result any
+	if *dryRun {
+		result, err = client.PreviewMerge(ctx, cl, *target, fs.Args(), opts)
+	} else {
+		result, err = client.MergeFeeds(ctx, cl, *target, fs.Args(), opts)
+	}
+	if err != nil {
+		return errorf("%v", err)
+	}
+
+	code := exitOK
+	if r, ok := result.(*client.MergeResult); ok && len(r.Failures) > 0 {
+		code = exitFailure
+	}
+	if *asJSON {
+		_ = json.NewEncoder(os.Stdout).Encode(result)
+		return code
+	}
+	switch r := result.(type) {
+	case *client.MergePreview:
+		printMergePreview(r)
+	case *client.MergeResult:
+		printMergePreview(&r.MergePreview)
+		fmt.Printf("added: %d, failed: %d\n", r.Added, len(r.Failures))
+	}
+	return code
+}
+
+func printMergePreview(p *client.MergePreview) {
+	fmt.Printf("target %s (%d posts) <- %d sources\n", p.Target, p.TargetPosts, len(p.Sources))
+	fmt.Printf("  read %d, duplicates %d (context conflicts %d), capped %d, already in target %d\n",
+		p.SourcePosts, p.Duplicates, p.ContextConflicts, p.Capped, p.AlreadyInTarget)
+	fmt.Printf("  to write %d, resulting size %d\n", p.ToWrite, p.ResultingSize)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// destAuthEnv names the auth variables for the destination of a migration.
+var destAuthEnv = authEnv{
+	apiKey:             "GYOKA_DEST_API_KEY",
+	accessClientID:     "CF_DEST_ACCESS_CLIENT_ID",
+	accessClientSecret: "CF_DEST_ACCESS_CLIENT_SECRET",
+}
+
+func runMigrate(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := fs.String("to", os.Getenv("GYOKA_DEST_SERVER"), "destination Gyoka editor API base URL (env GYOKA_DEST_SERVER)")
+	var feeds stringList
+	fs.Var(&feeds, "feed", "feed URI to migrate (repeatable, default all feeds)")
+	onConflict := fs.String("on-conflict", string(client.ConflictMerge), "what to do with feeds already on the destination: merge, overwrite, skip or fail")
+	batchSize := fs.Int("batch-size", 0, "posts per batchAddPosts request")
+	adaptive := fs.Bool("adaptive-batch", false, "tune the batch size from server responses, starting at -batch-size")
+	statePath := fs.String("state", "", "checkpoint file; rerun with the same file to resume")
+	verify := fs.Bool("verify", false, "compare source and destination after copying")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *to == "" {
+		fmt.Fprintln(os.Stderr, "gyokactl migrate: -to is required")
+		return exitUsage
+	}
+	strategy, err := client.ParseConflictStrategy(*onConflict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gyokactl migrate: %v\n", err)
+		return exitUsage
+	}
+
+	src, err := g.apiClient()
+	if err != nil {
+		return errorf("create source client: %v", err)
+	}
+	dst, err := g.newClient(*to, destAuthEnv)
+	if err != nil {
+		return errorf("create destination client: %v", err)
+	}
+
+	opts := client.MigrateOptions{
+		Feeds:      feeds,
+		OnConflict: strategy,
+		BatchSize:  *batchSize,
+		StatePath:  *statePath,
+		SourceURL:  g.server,
+		DestURL:    *to,
+		Verify:     *verify,
+	}
+	if *adaptive {
+		opts.Batcher = client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{Initial: *batchSize})
+	}
+	if !*asJSON {
+		opts.Progress = func(feed string, copied int) {
+			if opts.Batcher != nil {
+				fmt.Fprintf(os.Stderr, "%s: %d posts copied (batch size %d)\n", feed, copied, opts.Batcher.Size(client.OpPostBatchAddPosts))
+				return
+			}
+			fmt.Fprintf(os.Stderr, "%s: %d posts copied\n", feed, copied)
+		}
+	}
+	report, err := client.Migrate(ctx, src, dst, opts)
+	if *asJSON {
+		_ = json.NewEncoder(os.Stdout).Encode(report)
+	} else {
+		printMigrateReport(report)
+	}
+	if err != nil {
+		return errorf("%v", err)
+	}
+	if !report.OK() {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func printMigrateReport(r *client.MigrateReport) {
+	for _, f := range r.Feeds {
+		status := "copied"
+		switch {
+		case f.Skipped:
+			status = "skipped (exists on destination)"
+		case f.Error != "":
+			status = "error: " + f.Error
+		}
+		fmt.Printf("%s: %s, posts=%d failures=%d registered=%t conflict=%t resumed=%t\n",
+			f.Feed, status, f.Copied, len(f.Failures), f.Registered, f.Conflict, f.Resumed)
+		if v := f.Verify; v != nil {
+			fmt.Printf("  verify: source=%d destination=%d missing=%d mismatched=%d extra=%d\n",
+				v.SourcePosts, v.DestinationPosts, len(v.Missing), len(v.Mismatched), len(v.Extra))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// runRegistry implements "registry plan" and "registry apply".
+func runRegistry(ctx context.Context, g *globalFlags, args []string) int {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		fmt.Fprintln(os.Stderr, "usage: gyokactl registry plan|apply -manifest feeds.yaml [flags]")
+		return exitUsage
+	}
+	apply := args[0] == "apply"
+	fs := flag.NewFlagSet("registry "+args[0], flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "feeds.yaml", "feed manifest file")
+	prune := fs.Bool("prune", false, "unregister feeds that are missing from the manifest")
+	asJSON := fs.Bool("json", false, "print the plan or result as JSON")
+	var approveAll, interactive *bool
+	if apply {
+		approveAll = fs.Bool("approve-destructive", false, "approve all destructive actions without asking")
+		interactive = fs.Bool("interactive", false, "ask on the terminal before each destructive action")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	manifest, err := client.LoadFeedManifest(*manifestPath)
+	if err != nil {
+		return errorf("%v", err)
+	}
+	cl, err := g.apiClient()
+	if err != nil {
+		return errorf("create client: %v", err)
+	}
+	plan, err := manifest.Plan(ctx, cl, client.PlanOptions{Prune: *prune})
+	if err != nil {
+		return errorf("plan: %v", err)
+	}
+
+	if !apply {
+		if *asJSON {
+			_ = json.NewEncoder(os.Stdout).Encode(plan)
+		} else {
+			printPlan(plan)
+		}
+		return exitOK
+	}
+
+	if !*asJSON {
+		printPlan(plan)
+	}
+	opts := client.ApplyOptions{}
+	switch {
+	case *approveAll:
+		opts.Approve = func(client.PlanAction) bool { return true }
+	case *interactive:
+		in := bufio.NewReader(os.Stdin)
+		opts.Approve = func(a client.PlanAction) bool {
+			fmt.Printf("apply %q? [y/N] ", a.String())
+			answer, _ := in.ReadString('\n')
+			return strings.EqualFold(strings.TrimSpace(answer), "y")
+		}
+	}
+	result, err := plan.Apply(ctx, cl, opts)
+	if *asJSON {
+		_ = json.NewEncoder(os.Stdout).Encode(result)
+	} else {
+		for _, r := range result.Results {
+			if r.Applied {
+				fmt.Printf("done    %s\n", r.Action)
+			} else {
+				fmt.Printf("skipped %s: %s\n", r.Action, r.Error)
+			}
+		}
+	}
+	if err != nil {
+		return errorf("%v", err)
+	}
+	if result.Failed() > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func printPlan(p *client.Plan) {
+	fmt.Println(p)
+	for _, uri := range p.Unmanaged {
+		fmt.Printf("  (unmanaged, use -prune to unregister) %s\n", uri)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// removeAuthorResult is the JSON output of remove-author for one feed.
+type removeAuthorResult struct {
+	Feed    string `json:"feed"`
+	Removed int    `json:"removed"`
+	Error   string `json:"error,omitempty"`
+}
+
+func runRemoveAuthor(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("remove-author", flag.ContinueOnError)
+	author := fs.String("author", "", "DID of the author whose posts are removed")
+	concurrency := fs.Int("concurrency", 4, "number of feeds processed at once")
+	var feeds stringList
+	fs.Var(&feeds, "feed", "only process this feed URI (repeatable)")
+	journalDir := fs.String("journal", os.Getenv("GYOKA_JOURNAL"), "save the removed posts to this undo journal directory first (env GYOKA_JOURNAL)")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *author == "" {
+		fmt.Fprintln(os.Stderr, "gyokactl remove-author: -author is required")
+		return exitUsage
+	}
+
+	apiClient, err := g.apiClient()
+	if err != nil {
+		return errorf("create client: %v", err)
+	}
+	var cl client.ClientWithResponsesInterface = apiClient
+	if *journalDir != "" {
+		journal, err := client.NewUndoJournal(*journalDir)
+		if err != nil {
+			return errorf("-journal: %v", err)
+		}
+		cl = &client.SafeClient{ClientWithResponsesInterface: apiClient, Journal: journal}
+	}
+	opts := client.FanOutOptions{Concurrency: *concurrency, Feeds: feeds}
+	report, err := client.RemoveAuthorFromFeeds(ctx, cl, *author, opts)
+	if err != nil {
+		return errorf("%v", err)
+	}
+
+	results := make([]removeAuthorResult, len(report.Results))
+	total := 0
+	for i, res := range report.Results {
+		results[i] = removeAuthorResult{Feed: res.Feed, Removed: res.Value}
+		if res.Err != nil {
+			results[i].Error = res.Err.Error()
+		}
+		total += res.Value
+	}
+	failed := len(report.Failed())
+	if *asJSON {
+		_ = json.NewEncoder(os.Stdout).Encode(results)
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("%s: error: %s\n", r.Feed, r.Error)
+				continue
+			}
+			fmt.Printf("%s: removed %d\n", r.Feed, r.Removed)
+		}
+		fmt.Printf("removed: %d, feeds: %d, failed: %d\n", total, len(results), failed)
+	}
+	if failed > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
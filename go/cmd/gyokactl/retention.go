@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+func runRetention(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("retention", flag.ContinueOnError)
+	configPath := fs.String("config", "retention.yaml", "retention config file")
+	watch := fs.Bool("watch", false, "keep running and apply the policies every config interval")
+	asJSON := fs.Bool("json", false, "print reports as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	cfg, err := client.LoadRetentionConfig(*configPath)
+	if err != nil {
+		return errorf("%v", err)
+	}
+	cl, err := g.apiClient()
+	if err != nil {
+		return errorf("create client: %v", err)
+	}
+
+	s := client.NewRetentionScheduler(cl, cfg)
+	s.OnReport = func(r *client.RetentionReport) { printRetentionReport(r, *asJSON) }
+	if *watch {
+		if err := s.Run(ctx); err != nil && ctx.Err() == nil {
+			return errorf("%v", err)
+		}
+		return exitOK
+	}
+	if report := s.RunOnce(ctx); report.Failed() > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func printRetentionReport(r *client.RetentionReport, asJSON bool) {
+	if asJSON {
+		_ = json.NewEncoder(os.Stdout).Encode(r)
+		return
+	}
+	fmt.Printf("retention run %s (%s)\n", r.StartedAt.Format(time.RFC3339), r.FinishedAt.Sub(r.StartedAt).Round(time.Millisecond))
+	for _, res := range r.Results {
+		// A failed feed still reports what was removed before the error.
+		fmt.Printf("  %s: trimmed=%d expired=%d authorCapped=%d", res.Feed, res.Trimmed, res.Expired, res.AuthorCapped)
+		if res.Error != "" {
+			fmt.Printf(" error: %s", res.Error)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("  total deleted: %d\n", r.Deleted())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+// runUndo lists the undo journal, or restores the posts of the entry named
+// by the argument.
+func runUndo(ctx context.Context, g *globalFlags, args []string) int {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	journalDir := fs.String("journal", os.Getenv("GYOKA_JOURNAL"), "undo journal directory (env GYOKA_JOURNAL)")
+	asJSON := fs.Bool("json", false, "print the entries or result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gyokactl undo -journal DIR [flags] [JOURNAL_ID]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *journalDir == "" || fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	journal, err := client.NewUndoJournal(*journalDir)
+	if err != nil {
+		return errorf("-journal: %v", err)
+	}
+
+	if fs.NArg() == 0 {
+		entries, err := journal.List()
+		if err != nil {
+			return errorf("%v", err)
+		}
+		if *asJSON {
+			_ = json.NewEncoder(os.Stdout).Encode(entries)
+			return exitOK
+		}
+		for _, e := range entries {
+			state := "undoable"
+			if e.UndoneAt != nil {
+				state = "undone " + e.UndoneAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%s %s %s posts=%d %s\n", e.ID, e.Operation, e.Feed, len(e.Posts), state)
+		}
+		return exitOK
+	}
+
+	cl, err := g.apiClient()
+	if err != nil {
+		return errorf("create client: %v", err)
+	}
+	result, err := journal.Undo(ctx, cl, fs.Arg(0))
+	if *asJSON && result != nil {
+		_ = json.NewEncoder(os.Stdout).Encode(result)
+	} else if result != nil {
+		fmt.Printf("restored: %d of %d, failed: %d\n", result.Restored, len(result.Entry.Posts), len(result.Failures))
+		for _, f := range result.Failures {
+			fmt.Printf("  %s: %s\n", f.Uri, f.Error)
+		}
+	}
+	if err != nil {
+		return errorf("%v", err)
+	}
+	if len(result.Failures) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,416 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CoalescerOptions configures NewCoalescer.
+type CoalescerOptions struct {
+	// Window is how long the first call of a batch waits for others to
+	// join. Defaults to 10ms.
+	Window time.Duration
+	// MaxBatch sends a batch as soon as it holds this many posts. Defaults to
+	// 100.
+	MaxBatch int
+	// Timeout bounds a batch request when one of its callers has no
+	// deadline. Otherwise the request runs until the latest of the callers'
+	// deadlines. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// Coalescer is a ClientWithResponsesInterface that merges concurrent
+// PostAddPostWithResponse and PostRemovePostWithResponse calls into
+// PostBatchAddPosts and PostBatchRemovePosts requests. Every caller gets a
+// response as if it had made the single call: 200 with the post echoed
+// from its request, or the status and error code the single call would
+// have returned when its item failed. A batch rejected with 400 or 413,
+// which one post can cause, is resent as single calls so that only the
+// calls at fault fail; other failed batch requests are reported to all of
+// their callers. Concurrent calls for the same post and feed are sent
+// once, with the post of the first call, and share its result.
+//
+// Batch items report failures as free text only, so the status of a failed
+// item is inferred on a best-effort basis: a message saying the feed or post
+// does not exist or was not found becomes 404, anything else 400.
+//
+// Calls with request editors are passed through unbatched. A caller whose
+// context ends while waiting gets the context error, but its post may still
+// be sent. All other methods go straight to the wrapped client.
+type Coalescer struct {
+	ClientWithResponsesInterface
+	opts   CoalescerOptions
+	add    *coalesceQueue
+	remove *coalesceQueue
+}
+
+// NewCoalescer wraps c.
+func NewCoalescer(c ClientWithResponsesInterface, opts CoalescerOptions) *Coalescer {
+	if opts.Window <= 0 {
+		opts.Window = 10 * time.Millisecond
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = defaultBatchSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	co := &Coalescer{ClientWithResponsesInterface: c, opts: opts}
+	co.add = &coalesceQueue{opts: &co.opts, send: co.sendAdds}
+	co.remove = &coalesceQueue{opts: &co.opts, send: co.sendRemoves}
+	return co
+}
+
+// Unwrap returns the wrapped client.
+func (co *Coalescer) Unwrap() ClientWithResponsesInterface {
+	return co.ClientWithResponsesInterface
+}
+
+func (co *Coalescer) PostAddPostWithResponse(ctx context.Context, body PostAddPostJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAddPostResponse, error) {
+	if len(reqEditors) > 0 {
+		return co.ClientWithResponsesInterface.PostAddPostWithResponse(ctx, body, reqEditors...)
+	}
+	rsp, err := co.add.do(ctx, body.Feed, PostFromAddPostParam(body.Post))
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAddPostResponse(rsp)
+}
+
+func (co *Coalescer) PostRemovePostWithResponse(ctx context.Context, body PostRemovePostJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRemovePostResponse, error) {
+	if len(reqEditors) > 0 {
+		return co.ClientWithResponsesInterface.PostRemovePostWithResponse(ctx, body, reqEditors...)
+	}
+	post := Post{Uri: body.Post.Uri}
+	if body.Post.IndexedAt != nil {
+		post.IndexedAt = *body.Post.IndexedAt
+	}
+	rsp, err := co.remove.do(ctx, body.Feed, post)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostRemovePostResponse(rsp)
+}
+
+// coalesceQueue collects the calls of one operation until they are sent.
+type coalesceQueue struct {
+	opts *CoalescerOptions
+	// send sends a batch and delivers a result to every call.
+	send func(ctx context.Context, calls []*coalescedCall)
+
+	mu      sync.Mutex
+	pending []*coalescedCall
+	timer   *time.Timer
+}
+
+type coalescedCall struct {
+	// ctx is the caller's context, used for the batch request's values and
+	// deadline.
+	ctx  context.Context
+	feed string
+	post Post
+	done chan coalescedResult
+}
+
+// coalescedResult is the single-call response for one coalescedCall.
+type coalescedResult struct {
+	status int
+	body   []byte
+	err    error
+}
+
+func (q *coalesceQueue) do(ctx context.Context, feed string, post Post) (*http.Response, error) {
+	call := &coalescedCall{ctx: ctx, feed: feed, post: post, done: make(chan coalescedResult, 1)}
+	q.mu.Lock()
+	q.pending = append(q.pending, call)
+	var batch []*coalescedCall
+	switch {
+	case len(q.pending) >= q.opts.MaxBatch:
+		batch = q.take()
+	case len(q.pending) == 1:
+		q.timer = time.AfterFunc(q.opts.Window, func() {
+			q.mu.Lock()
+			batch := q.take()
+			q.mu.Unlock()
+			if len(batch) > 0 {
+				q.flush(batch)
+			}
+		})
+	}
+	q.mu.Unlock()
+	if batch != nil {
+		q.flush(batch)
+	}
+
+	select {
+	case r := <-call.done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return newResponse(nil, r.status, http.Header{"Content-Type": {"application/json"}}, string(r.body)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take empties the queue. The caller holds q.mu.
+func (q *coalesceQueue) take() []*coalescedCall {
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	batch := q.pending
+	q.pending = nil
+	return batch
+}
+
+// flush sends batch. Values such as the trace span of the first caller carry
+// over to the batch request; cancellation does not. The request runs until
+// the latest of the callers' deadlines, counting Timeout from now for
+// callers without one.
+func (q *coalesceQueue) flush(batch []*coalescedCall) {
+	now := time.Now()
+	var deadline time.Time
+	for _, c := range batch {
+		d, ok := c.ctx.Deadline()
+		if !ok {
+			d = now.Add(q.opts.Timeout)
+		}
+		if d.After(deadline) {
+			deadline = d
+		}
+	}
+	ctx, cancel := context.WithDeadline(context.WithoutCancel(batch[0].ctx), deadline)
+	defer cancel()
+	q.send(ctx, batch)
+}
+
+// postKey identifies a post in a feed.
+type postKey struct {
+	feed, uri string
+}
+
+// groupCalls returns the feeds of calls in order of appearance, the posts to
+// send for each feed and the calls waiting for each post. A post requested
+// by several calls is sent once, as given by the first of them.
+func groupCalls(calls []*coalescedCall) (feeds []string, posts map[string][]Post, byPost map[postKey][]*coalescedCall) {
+	posts = make(map[string][]Post)
+	byPost = make(map[postKey][]*coalescedCall)
+	for _, c := range calls {
+		key := postKey{c.feed, c.post.Uri}
+		if _, ok := byPost[key]; !ok {
+			if _, ok := posts[c.feed]; !ok {
+				feeds = append(feeds, c.feed)
+			}
+			posts[c.feed] = append(posts[c.feed], c.post)
+		}
+		byPost[key] = append(byPost[key], c)
+	}
+	return feeds, posts, byPost
+}
+
+func (co *Coalescer) sendAdds(ctx context.Context, calls []*coalescedCall) {
+	feeds, posts, byPost := groupCalls(calls)
+	var body PostBatchAddPostsJSONRequestBody
+	for _, feed := range feeds {
+		body.Entries = append(body.Entries, NewBatchAddPostsBody(feed, posts[feed]).Entries...)
+	}
+	sent := time.Now().UTC()
+	rsp, err := PostBatchAddPostsPooled(ctx, co.ClientWithResponsesInterface, body)
+	if err != nil {
+		deliverError(calls, err)
+		return
+	}
+	if rsp.StatusCode() != http.StatusOK {
+		if isolatable(rsp.StatusCode(), byPost) {
+			sendSingly(byPost, func(c *coalescedCall) (*http.Response, []byte, error) {
+				rsp, err := co.ClientWithResponsesInterface.PostAddPostWithResponse(ctx, PostAddPostJSONRequestBody{Feed: c.feed, Post: c.post.AddPostParam()})
+				if err != nil {
+					return nil, nil, err
+				}
+				return rsp.HTTPResponse, rsp.Body, nil
+			})
+			return
+		}
+		deliverStatus(calls, rsp.StatusCode(), rsp.Body)
+		return
+	}
+	if rsp.JSON200 == nil {
+		deliverError(calls, newAPIError(OpPostBatchAddPosts, rsp.HTTPResponse, rsp.Body))
+		return
+	}
+	deliverItems(byPost, calls, rsp.Items(), func(c *coalescedCall, item BatchItemResult) coalescedResult {
+		if !item.OK() {
+			return itemError(item, ErrorCodeUnknownFeed)
+		}
+		return addedPostResult(c, sent)
+	})
+}
+
+func (co *Coalescer) sendRemoves(ctx context.Context, calls []*coalescedCall) {
+	feeds, posts, byPost := groupCalls(calls)
+	var body PostBatchRemovePostsJSONRequestBody
+	for _, feed := range feeds {
+		body.Entries = append(body.Entries, NewBatchRemovePostsBody(feed, posts[feed]).Entries...)
+	}
+	rsp, err := PostBatchRemovePostsPooled(ctx, co.ClientWithResponsesInterface, body)
+	if err != nil {
+		deliverError(calls, err)
+		return
+	}
+	if rsp.StatusCode() != http.StatusOK {
+		if isolatable(rsp.StatusCode(), byPost) {
+			sendSingly(byPost, func(c *coalescedCall) (*http.Response, []byte, error) {
+				rsp, err := co.ClientWithResponsesInterface.PostRemovePostWithResponse(ctx, PostRemovePostJSONRequestBody{Feed: c.feed, Post: c.post.RemovePostParam()})
+				if err != nil {
+					return nil, nil, err
+				}
+				return rsp.HTTPResponse, rsp.Body, nil
+			})
+			return
+		}
+		deliverStatus(calls, rsp.StatusCode(), rsp.Body)
+		return
+	}
+	if rsp.JSON200 == nil {
+		deliverError(calls, newAPIError(OpPostBatchRemovePosts, rsp.HTTPResponse, rsp.Body))
+		return
+	}
+	deliverItems(byPost, calls, rsp.Items(), func(c *coalescedCall, item BatchItemResult) coalescedResult {
+		if !item.OK() {
+			return itemError(item, ErrorCodeNotFound)
+		}
+		// Batch items do not say when the removed post was indexed, so
+		// indexedAt is left out of the echo.
+		var out struct {
+			Feed    string `json:"feed"`
+			Message string `json:"message"`
+			Post    struct {
+				Uri string `json:"uri"`
+			} `json:"post"`
+		}
+		out.Feed, out.Message = c.feed, "Post removed successfully"
+		out.Post.Uri = c.post.Uri
+		return jsonResult(http.StatusOK, out)
+	})
+}
+
+// addedPostResult echoes c's post like a successful addPost. Posts sent
+// without indexedAt get the time the batch was sent.
+func addedPostResult(c *coalescedCall, sent time.Time) coalescedResult {
+	type reason struct {
+		Type   ReasonType `json:"$type"`
+		Repost *string    `json:"repost,omitempty"`
+	}
+	var out struct {
+		Feed    string `json:"feed"`
+		Message string `json:"message"`
+		Post    struct {
+			Uri         string    `json:"uri"`
+			Cid         string    `json:"cid"`
+			IndexedAt   time.Time `json:"indexedAt"`
+			Languages   []string  `json:"languages"`
+			FeedContext *string   `json:"feedContext,omitempty"`
+			Reason      *reason   `json:"reason,omitempty"`
+		} `json:"post"`
+	}
+	p := c.post
+	out.Feed, out.Message = c.feed, "Post added successfully"
+	out.Post.Uri, out.Post.Cid, out.Post.IndexedAt = p.Uri, p.Cid, p.IndexedAt
+	if out.Post.IndexedAt.IsZero() {
+		out.Post.IndexedAt = sent
+	}
+	out.Post.Languages, out.Post.FeedContext = p.Languages, p.FeedContext
+	if out.Post.Languages == nil {
+		out.Post.Languages = []string{}
+	}
+	if p.Reason != nil {
+		out.Post.Reason = &reason{Type: p.Reason.Type, Repost: p.Reason.Repost}
+	}
+	return jsonResult(http.StatusOK, out)
+}
+
+// itemError builds the single-call error for a failed item. The status is
+// guessed from the free-text message: 404 with notFoundCode when it says
+// something does not exist or was not found, 400 BadRequest otherwise.
+func itemError(item BatchItemResult, notFoundCode string) coalescedResult {
+	status, code := http.StatusBadRequest, ErrorCodeBadRequest
+	if strings.Contains(item.Error, "does not exist") || strings.Contains(item.Error, "not found") {
+		status, code = http.StatusNotFound, notFoundCode
+	}
+	return jsonResult(status, struct {
+		Error   string `json:"error"`
+		Message string `json:"message,omitempty"`
+	}{code, item.Error})
+}
+
+func jsonResult(status int, v any) coalescedResult {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return coalescedResult{err: err}
+	}
+	return coalescedResult{status: status, body: body}
+}
+
+// deliverItems hands every call the result built from the batch item for
+// its feed and post URI. The result is built for the first call of a post,
+// whose post was sent, and shared by the others.
+func deliverItems(byPost map[postKey][]*coalescedCall, calls []*coalescedCall, items []BatchItemResult, result func(*coalescedCall, BatchItemResult) coalescedResult) {
+	delivered := make(map[*coalescedCall]bool)
+	for _, item := range items {
+		group := byPost[postKey{item.Feed, item.Uri}]
+		if len(group) == 0 || delivered[group[0]] {
+			continue
+		}
+		r := result(group[0], item)
+		for _, c := range group {
+			c.done <- r
+			delivered[c] = true
+		}
+	}
+	for _, c := range calls {
+		if !delivered[c] {
+			c.done <- coalescedResult{err: fmt.Errorf("gyoka: batch response has no result for %s in %s", c.post.Uri, c.feed)}
+		}
+	}
+}
+
+// isolatable reports whether a batch that failed with status may have
+// failed because of some of its posts, and holds more than one post.
+func isolatable(status int, byPost map[postKey][]*coalescedCall) bool {
+	return len(byPost) > 1 && (status == http.StatusBadRequest || status == http.StatusRequestEntityTooLarge)
+}
+
+// sendSingly sends every post of a failed batch with send and hands each
+// call the response of its own post.
+func sendSingly(byPost map[postKey][]*coalescedCall, send func(*coalescedCall) (*http.Response, []byte, error)) {
+	for _, group := range byPost {
+		var r coalescedResult
+		rsp, body, err := send(group[0])
+		if err != nil {
+			r.err = err
+		} else {
+			r.status, r.body = rsp.StatusCode, body
+		}
+		for _, c := range group {
+			c.done <- r
+		}
+	}
+}
+
+func deliverStatus(calls []*coalescedCall, status int, body []byte) {
+	for _, c := range calls {
+		c.done <- coalescedResult{status: status, body: body}
+	}
+}
+
+func deliverError(calls []*coalescedCall, err error) {
+	for _, c := range calls {
+		c.done <- coalescedResult{err: err}
+	}
+}
@@ -0,0 +1,116 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestCoalescerIsolatesRejectedBatch(t *testing.T) {
+	ms := server.NewMemoryServer()
+	poison := testPostURI(3)
+	var batches atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if strings.HasSuffix(r.URL.Path, "/batchAddPosts") {
+			batches.Add(1)
+		}
+		if strings.Contains(string(body), poison) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":"BadRequest","message":"malformed post"}`)
+			return
+		}
+		ms.Handler().ServeHTTP(w, r)
+	}))
+	registerFeed(t, c, testFeed)
+	co := client.NewCoalescer(c, client.CoalescerOptions{Window: 50 * time.Millisecond})
+
+	posts := testPosts(6)
+	status := make([]int, len(posts))
+	var wg sync.WaitGroup
+	for i, p := range posts {
+		wg.Go(func() {
+			rsp, err := co.PostAddPostWithResponse(context.Background(), client.PostAddPostJSONRequestBody{Feed: testFeed, Post: p.AddPostParam()})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			status[i] = rsp.StatusCode()
+		})
+	}
+	wg.Wait()
+
+	if n := batches.Load(); n != 1 {
+		t.Errorf("sent %d batch requests, want 1", n)
+	}
+	for i, p := range posts {
+		want := http.StatusOK
+		if p.Uri == poison {
+			want = http.StatusBadRequest
+		}
+		if status[i] != want {
+			t.Errorf("%s: status %d, want %d", p.Uri, status[i], want)
+		}
+	}
+	if stored := feedURIs(t, c, testFeed); len(stored) != len(posts)-1 || stored[poison] {
+		t.Errorf("feed holds %v, want every post but %s", stored, poison)
+	}
+}
+
+func TestCoalescerItemResults(t *testing.T) {
+	c, _ := newMemoryClient(t)
+	co := client.NewCoalescer(c, client.CoalescerOptions{Window: 50 * time.Millisecond})
+	post := testPosts(1)[0]
+	unknownFeed := testFeed + "-unknown"
+
+	type result struct {
+		feed   string
+		status int
+		uri    string
+	}
+	results := make(chan result, 4)
+	var wg sync.WaitGroup
+	for _, feed := range []string{testFeed, testFeed, testFeed, unknownFeed} {
+		wg.Go(func() {
+			rsp, err := co.PostAddPostWithResponse(context.Background(), client.PostAddPostJSONRequestBody{Feed: feed, Post: post.AddPostParam()})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			r := result{feed: feed, status: rsp.StatusCode()}
+			if rsp.JSON200 != nil {
+				r.uri = rsp.JSON200.Post.Uri
+			}
+			results <- r
+		})
+	}
+	wg.Wait()
+	close(results)
+	for r := range results {
+		switch {
+		case r.feed == unknownFeed && r.status != http.StatusNotFound:
+			t.Errorf("unknown feed: status %d, want 404", r.status)
+		case r.feed == testFeed && (r.status != http.StatusOK || r.uri != post.Uri):
+			t.Errorf("duplicate call: status %d uri %q, want 200 %s", r.status, r.uri, post.Uri)
+		}
+	}
+
+	rsp, err := co.PostRemovePostWithResponse(context.Background(), client.PostRemovePostJSONRequestBody{Feed: testFeed, Post: client.RemovePostPostParam{Uri: post.Uri}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode() != http.StatusOK || rsp.JSON200 == nil || !rsp.JSON200.Post.IndexedAt.IsZero() {
+		t.Errorf("remove: status %d body %s, want 200 without indexedAt", rsp.StatusCode(), rsp.Body)
+	}
+}
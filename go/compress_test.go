@@ -0,0 +1,123 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// benchClient returns a client whose Doer reads and closes the request
+// body and answers an empty batch result, so the benchmarks measure only
+// request encoding.
+func benchClient(b *testing.B, opts ...ClientOption) *ClientWithResponses {
+	b.Helper()
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		return newResponse(req, http.StatusOK, http.Header{"Content-Type": {"application/json"}}, `{"results":[]}`), nil
+	})
+	c, err := NewClientWithResponses("http://gyoka.test", append([]ClientOption{WithHTTPClient(doer)}, opts...)...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func benchBatchBody() PostBatchAddPostsJSONRequestBody {
+	posts := make([]Post, defaultBatchSize)
+	fc := "context"
+	for i := range posts {
+		posts[i] = Post{
+			Uri:         fmt.Sprintf("at://did:plc:abcdefghijklmnopqrstuvwx/app.bsky.feed.post/3k%011d", i),
+			Cid:         "bafyreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+			IndexedAt:   time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+			Languages:   []string{"ja", "en"},
+			FeedContext: &fc,
+		}
+	}
+	return NewBatchAddPostsBody("at://did:plc:owner/app.bsky.feed.generator/bench", posts)
+}
+
+// BenchmarkNewPostBatchAddPostsRequest is the baseline: the generated
+// client encodes the body with json.Marshal in NewPostBatchAddPostsRequest.
+func BenchmarkNewPostBatchAddPostsRequest(b *testing.B) {
+	c, body, ctx := benchClient(b), benchBatchBody(), context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.PostBatchAddPostsWithResponse(ctx, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPostBatchAddPostsPooled(b *testing.B) {
+	c, body, ctx := benchClient(b), benchBatchBody(), context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := PostBatchAddPostsPooled(ctx, c, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPostBatchAddPostsPooledGzip(b *testing.B) {
+	c, body, ctx := benchClient(b, WithCompression(CompressionOptions{})), benchBatchBody(), context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := PostBatchAddPostsPooled(ctx, c, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCompressionFallsBackOn415(t *testing.T) {
+	var encodings []string
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		defer req.Body.Close()
+		enc := req.Header.Get("Content-Encoding")
+		encodings = append(encodings, enc)
+		var r io.Reader = req.Body
+		if enc == "gzip" {
+			zr, err := gzip.NewReader(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			r = zr
+		}
+		var body PostBatchAddPostsJSONRequestBody
+		if err := json.NewDecoder(r).Decode(&body); err != nil {
+			return nil, err
+		}
+		if n := len(body.Entries[0].Posts); n != defaultBatchSize {
+			t.Errorf("request %d carries %d posts, want %d", len(encodings), n, defaultBatchSize)
+		}
+		if len(encodings) == 1 {
+			return newResponse(req, http.StatusUnsupportedMediaType, http.Header{"Content-Type": {"application/json"}}, `{"error":"UnsupportedMediaType"}`), nil
+		}
+		return newResponse(req, http.StatusOK, http.Header{"Content-Type": {"application/json"}}, `{"results":[]}`), nil
+	})
+	c, err := NewClientWithResponses("http://gyoka.test", WithHTTPClient(doer), WithCompression(CompressionOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		rsp, err := PostBatchAddPostsPooled(context.Background(), c, benchBatchBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.StatusCode() != http.StatusOK {
+			t.Fatalf("status %d, want 200", rsp.StatusCode())
+		}
+	}
+	if want := []string{"gzip", "", ""}; !slices.Equal(encodings, want) {
+		t.Errorf("encodings = %q, want %q", encodings, want)
+	}
+}
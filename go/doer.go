@@ -0,0 +1,43 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DoerFunc adapts a function to HttpRequestDoer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// wrapDoer replaces the client's HttpRequestDoer with wrap(doer). The default
+// http.Client is created first when none was set, so instrumentation options
+// must come after WithHTTPClient.
+func wrapDoer(c *Client, wrap func(HttpRequestDoer) HttpRequestDoer) {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	c.Client = wrap(c.Client)
+}
+
+// newResponse builds a response to req that was not received from the server.
+func newResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
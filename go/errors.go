@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error codes returned by the Gyoka API in the "error" field of non-200 responses.
+const (
+	ErrorCodeBadRequest          = "BadRequest"
+	ErrorCodeUnauthorized        = "Unauthorized"
+	ErrorCodeUnknownFeed         = "UnknownFeed"
+	ErrorCodeNotFound            = "NotFound"
+	ErrorCodeConflict            = "Conflict"
+	ErrorCodeInternalServerError = "InternalServerError"
+)
+
+// APIError describes a non-200 response from the Gyoka API.
+type APIError struct {
+	// Operation is the name of the call that failed, e.g. "postTrimFeed".
+	Operation  string
+	StatusCode int
+	// Code is the Gyoka error code, if the body carried one.
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("gyoka: %s: unexpected status %d", e.Operation, e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from a response that was not handled as a success.
+func newAPIError(op string, rsp *http.Response, body []byte) *APIError {
+	e := &APIError{Operation: op}
+	if rsp != nil {
+		e.StatusCode = rsp.StatusCode
+	}
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		e.Code = payload.Error
+		e.Message = payload.Message
+	}
+	return e
+}
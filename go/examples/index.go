@@ -42,9 +42,12 @@ func main() {
 			customHeaders: ch,
 			transport:     baseTransport,
 		},
-		Timeout: defaultTimeout,
 	}
-	cl, err := client.NewClientWithResponses("http://localhost:8787", client.WithHTTPClient(hc))
+	// per-operation deadlines instead of one http.Client timeout
+	cl, err := client.NewClientWithResponses("http://localhost:8787",
+		client.WithHTTPClient(hc),
+		client.WithOperationTimeouts(client.TimeoutOptions{Default: defaultTimeout}),
+	)
 	if err != nil {
 		log.Fatalf("failed to create client: %v", err)
 	}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FanOutOptions configures ForEachFeed.
+type FanOutOptions struct {
+	// Concurrency is the number of feeds processed at once. Defaults to 4.
+	Concurrency int
+	// Filter selects the feeds to process. Nil processes every feed.
+	Filter func(FeedSettings) bool
+	// Feeds limits the run to these feed URIs. A listed feed that
+	// GetListFeeds does not return is recorded as failed with
+	// ErrUnknownFeed after the others.
+	Feeds []string
+}
+
+const defaultFanOutConcurrency = 4
+
+// ErrUnknownFeed is the FeedResult error of a FanOutOptions.Feeds entry
+// that is not registered.
+var ErrUnknownFeed = errors.New("gyoka: feed is not registered")
+
+// FeedResult is the outcome of the per-feed function for one feed.
+type FeedResult[T any] struct {
+	Feed     string
+	Value    T
+	Err      error
+	Duration time.Duration
+}
+
+// FanOutReport holds the results of ForEachFeed in the order of the feed
+// list, whatever order the feeds finished in.
+type FanOutReport[T any] struct {
+	Results []FeedResult[T]
+}
+
+// Failed returns the results whose function returned an error, including
+// feeds that were not started because the context ended.
+func (r *FanOutReport[T]) Failed() []FeedResult[T] {
+	var failed []FeedResult[T]
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err returns a *FanOutError when any feed failed, and nil otherwise.
+func (r *FanOutReport[T]) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	e := &FanOutError{Total: len(r.Results)}
+	for _, res := range failed {
+		e.Feeds = append(e.Feeds, res.Feed)
+		e.Errs = append(e.Errs, res.Err)
+	}
+	return e
+}
+
+// FanOutError reports the feeds that failed in a ForEachFeed run. It
+// unwraps to the per-feed errors, so errors.Is and errors.As see each of
+// them.
+type FanOutError struct {
+	Total int
+	Feeds []string
+	Errs  []error
+}
+
+func (e *FanOutError) Error() string {
+	msg := fmt.Sprintf("gyoka: %d of %d feeds failed", len(e.Feeds), e.Total)
+	for i, feed := range e.Feeds {
+		msg += fmt.Sprintf("\n%s: %v", feed, e.Errs[i])
+	}
+	return msg
+}
+
+func (e *FanOutError) Unwrap() []error { return e.Errs }
+
+// ForEachFeed runs fn for every feed returned by GetListFeeds, at most
+// Concurrency at a time. An error or panic in fn is recorded for its feed
+// and does not stop the others. When ctx ends, feeds not yet started are
+// recorded with ctx.Err() and running ones see the cancelled ctx. The
+// returned error is only set when the feed list could not be retrieved; use
+// FanOutReport.Err for per-feed failures.
+func ForEachFeed[T any](ctx context.Context, c ClientWithResponsesInterface, opts FanOutOptions, fn func(ctx context.Context, feed FeedSettings) (T, error)) (*FanOutReport[T], error) {
+	feeds, err := ListFeeds(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	if len(opts.Feeds) > 0 {
+		for _, uri := range opts.Feeds {
+			if !slices.Contains(unknown, uri) && !slices.ContainsFunc(feeds, func(f FeedSettings) bool { return f.Uri == uri }) {
+				unknown = append(unknown, uri)
+			}
+		}
+		feeds = slices.DeleteFunc(feeds, func(f FeedSettings) bool { return !slices.Contains(opts.Feeds, f.Uri) })
+	}
+	if opts.Filter != nil {
+		feeds = slices.DeleteFunc(feeds, func(f FeedSettings) bool { return !opts.Filter(f) })
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFanOutConcurrency
+	}
+
+	report := &FanOutReport[T]{Results: make([]FeedResult[T], len(feeds), len(feeds)+len(unknown))}
+	for _, uri := range unknown {
+		report.Results = append(report.Results, FeedResult[T]{Feed: uri, Err: ErrUnknownFeed})
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		res := &report.Results[i]
+		res.Feed = feed.Uri
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			res.Err = ctx.Err()
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			start := time.Now()
+			res.Value, res.Err = callFeed(ctx, feed, fn)
+			res.Duration = time.Since(start)
+		})
+	}
+	wg.Wait()
+	return report, nil
+}
+
+func callFeed[T any](ctx context.Context, feed FeedSettings, fn func(context.Context, FeedSettings) (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gyoka: panic: %v", r)
+		}
+	}()
+	return fn(ctx, feed)
+}
+
+// RemoveAuthor removes every post of author from feed and returns the
+// number of posts removed.
+func RemoveAuthor(ctx context.Context, c ClientWithResponsesInterface, feed, author string) (int, error) {
+	resp, err := c.PostRemovePostByAuthorWithResponse(ctx, PostRemovePostByAuthorJSONRequestBody{Feed: feed, Author: author})
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return 0, newAPIError(OpPostRemovePostByAuthor, resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200.DeletedCount, nil
+}
+
+// RemoveAuthorFromFeeds removes every post of author from all feeds
+// selected by opts. Each result holds the number of posts removed from its
+// feed.
+func RemoveAuthorFromFeeds(ctx context.Context, c ClientWithResponsesInterface, author string, opts FanOutOptions) (*FanOutReport[int], error) {
+	if author == "" {
+		return nil, errors.New("gyoka: author is required")
+	}
+	return ForEachFeed(ctx, c, opts, func(ctx context.Context, feed FeedSettings) (int, error) {
+		return RemoveAuthor(ctx, c, feed.Uri, author)
+	})
+}
@@ -0,0 +1,34 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+func TestRemoveAuthorFromUnknownFeed(t *testing.T) {
+	c, _ := newMemoryClient(t)
+	if _, err := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{}).AddPosts(context.Background(), c, testFeed, testPosts(3)); err != nil {
+		t.Fatal(err)
+	}
+	const unknown = "at://did:plc:owner/app.bsky.feed.generator/unknown"
+
+	report, err := client.RemoveAuthorFromFeeds(context.Background(), c, "did:plc:author", client.FanOutOptions{Feeds: []string{unknown, testFeed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(report.Results))
+	}
+	if res := report.Results[0]; res.Feed != testFeed || res.Err != nil || res.Value != 3 {
+		t.Errorf("got %+v, want 3 posts removed from %s", res, testFeed)
+	}
+	if res := report.Results[1]; res.Feed != unknown || !errors.Is(res.Err, client.ErrUnknownFeed) {
+		t.Errorf("got %+v, want %s to fail with ErrUnknownFeed", res, unknown)
+	}
+	if !errors.Is(report.Err(), client.ErrUnknownFeed) {
+		t.Errorf("report.Err() = %v, want ErrUnknownFeed", report.Err())
+	}
+}
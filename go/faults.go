@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultKind selects what a FaultInjector does to a call.
+type FaultKind string
+
+const (
+	// FaultLatency delays the call by Fault.Latency and then sends it.
+	FaultLatency FaultKind = "latency"
+	// FaultDrop fails the call with ErrFaultDropped. With Fault.AfterSend the
+	// request reaches the server first and only the response is lost.
+	FaultDrop FaultKind = "drop"
+	// FaultStatus answers with Fault.StatusCode without sending the request.
+	// 429 carries a Retry-After header, 502 and 503 a non-JSON body like a
+	// proxy would send.
+	FaultStatus FaultKind = "status"
+	// FaultTruncate cuts the response body in half and fails the read with
+	// io.ErrUnexpectedEOF.
+	FaultTruncate FaultKind = "truncate"
+	// FaultContentType replaces the response Content-Type with text/plain.
+	FaultContentType FaultKind = "contentType"
+	// FaultCorruptJSON cuts the response body in half so it no longer parses.
+	FaultCorruptJSON FaultKind = "corruptJSON"
+)
+
+// ErrFaultDropped is the error returned for FaultDrop.
+var ErrFaultDropped = errors.New("gyoka: connection dropped by fault injector")
+
+// Fault is the misbehaviour a FaultRule injects.
+type Fault struct {
+	Kind FaultKind
+	// Latency is the delay for FaultLatency.
+	Latency time.Duration
+	// StatusCode is the status for FaultStatus, e.g. 429, 500, 502 or 503.
+	StatusCode int
+	// AfterSend makes FaultDrop send the request before failing.
+	AfterSend bool
+}
+
+// FaultRule selects the calls a Fault applies to. Rules are checked in
+// order and the first one that fires wins.
+type FaultRule struct {
+	// Operation restricts the rule to one operation, e.g. OpPostBatchAddPosts.
+	// Empty matches every call.
+	Operation string
+	// Nth fires the rule only on the nth matching call, counting from 1.
+	Nth int
+	// Probability fires the rule on a random share of matching calls. It is
+	// ignored when Nth is set; with neither set the rule always fires.
+	Probability float64
+	Fault       Fault
+}
+
+// FaultOptions configures NewFaultInjector.
+type FaultOptions struct {
+	// Next sends the calls. Defaults to http.DefaultClient.
+	Next  HttpRequestDoer
+	Rules []FaultRule
+	// Seed makes Probability rules reproducible.
+	Seed uint64
+	// OnFault is called for every injected fault.
+	OnFault func(op string, f Fault)
+}
+
+// FaultInjector is an HttpRequestDoer that injects faults into Gyoka calls
+// so retry and batching code can be tested against the error paths of the
+// Parse*Response functions.
+type FaultInjector struct {
+	opts FaultOptions
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	calls []int
+}
+
+// NewFaultInjector creates a FaultInjector. Pass it to WithHTTPClient.
+func NewFaultInjector(opts FaultOptions) *FaultInjector {
+	if opts.Next == nil {
+		opts.Next = http.DefaultClient
+	}
+	return &FaultInjector{
+		opts:  opts,
+		rand:  rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+		calls: make([]int, len(opts.Rules)),
+	}
+}
+
+func (f *FaultInjector) Do(req *http.Request) (*http.Response, error) {
+	op := operationName(req)
+	fault, ok := f.pick(op)
+	if !ok {
+		return f.opts.Next.Do(req)
+	}
+	if f.opts.OnFault != nil {
+		f.opts.OnFault(op, fault)
+	}
+
+	switch fault.Kind {
+	case FaultLatency:
+		t := time.NewTimer(fault.Latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return f.opts.Next.Do(req)
+	case FaultDrop:
+		if fault.AfterSend {
+			rsp, err := f.opts.Next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			rsp.Body.Close()
+		}
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrFaultDropped)
+	case FaultStatus:
+		return statusResponse(req, fault.StatusCode), nil
+	}
+
+	rsp, err := f.opts.Next.Do(req)
+	if err != nil {
+		return rsp, err
+	}
+	switch fault.Kind {
+	case FaultContentType:
+		rsp.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	case FaultTruncate, FaultCorruptJSON:
+		body, err := io.ReadAll(rsp.Body)
+		rsp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		half := bytes.NewReader(body[:len(body)/2])
+		rsp.Body = io.NopCloser(half)
+		rsp.ContentLength = int64(half.Len())
+		rsp.Header.Del("Content-Length")
+		if fault.Kind == FaultTruncate {
+			rsp.Body = io.NopCloser(io.MultiReader(half, errReader{io.ErrUnexpectedEOF}))
+			rsp.ContentLength = int64(len(body))
+		}
+	}
+	return rsp, nil
+}
+
+// pick returns the fault of the first rule that fires for op.
+func (f *FaultInjector) pick(op string) (Fault, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, r := range f.opts.Rules {
+		if r.Operation != "" && r.Operation != op {
+			continue
+		}
+		f.calls[i]++
+		switch {
+		case r.Nth > 0:
+			if f.calls[i] != r.Nth {
+				continue
+			}
+		case r.Probability > 0:
+			if f.rand.Float64() >= r.Probability {
+				continue
+			}
+		}
+		return r.Fault, true
+	}
+	return Fault{}, false
+}
+
+// statusResponse builds the response a FaultStatus fault returns.
+func statusResponse(req *http.Request, status int) *http.Response {
+	header := make(http.Header)
+	var body string
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		header.Set("Content-Type", "text/html; charset=utf-8")
+		body = fmt.Sprintf("<html><body><h1>%d %s</h1></body></html>", status, http.StatusText(status))
+	default:
+		if status == http.StatusTooManyRequests {
+			header.Set("Retry-After", "1")
+		}
+		code := ErrorCodeInternalServerError
+		if status < 500 {
+			code = strings.ReplaceAll(http.StatusText(status), " ", "")
+		}
+		header.Set("Content-Type", "application/json")
+		body = fmt.Sprintf(`{"error":%q,"message":"injected fault"}`, code)
+	}
+	return newResponse(req, status, header, body)
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// unknownFeedOps are the operations that answer 404 UnknownFeed for a feed
+// that is not registered.
+var unknownFeedOps = map[string]bool{
+	OpPostAddPost:            true,
+	OpGetGetPosts:            true,
+	OpPostRemovePostByAuthor: true,
+	OpPostTrimFeed:           true,
+	OpPostUnregisterFeed:     true,
+	OpPostUpdateFeed:         true,
+}
+
+// FeedCacheOptions configures NewFeedCache.
+type FeedCacheOptions struct {
+	// TTL is how long a listFeeds result is served from the cache. Defaults
+	// to one minute.
+	TTL time.Duration
+	// NegativeTTL is how long a feed that got a 404 UnknownFeed is answered
+	// with UnknownFeed without asking the server. Defaults to TTL.
+	NegativeTTL time.Duration
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// FeedCache caches feed settings for the clients created with
+// WithFeedCache(cache). GetListFeeds is served from the cache while it is
+// fresh, and concurrent misses share one request. Successful registerFeed,
+// updateFeed and unregisterFeed calls update the cache, and calls for a feed
+// that recently got a 404 UnknownFeed are answered without a request until
+// a registerFeed for it succeeds or answers 409.
+type FeedCache struct {
+	opts FeedCacheOptions
+
+	mu        sync.Mutex
+	feeds     []FeedSettings
+	fetchedAt time.Time
+	// generation changes with every update so a listFeeds result that
+	// raced a write is not stored.
+	generation uint64
+	unknown    map[string]time.Time
+	inflight   *feedListCall
+}
+
+type feedListCall struct {
+	done   chan struct{}
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// NewFeedCache creates an empty FeedCache. One cache can be shared by
+// clients of the same Gyoka instance.
+func NewFeedCache(opts FeedCacheOptions) *FeedCache {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = opts.TTL
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &FeedCache{opts: opts, unknown: make(map[string]time.Time)}
+}
+
+// WithFeedCache wraps the client's HttpRequestDoer with cache. It must come
+// after WithHTTPClient.
+func WithFeedCache(cache *FeedCache) ClientOption {
+	return func(c *Client) error {
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				return cache.do(req, next)
+			})
+		})
+		return nil
+	}
+}
+
+// Invalidate drops all cached feeds and UnknownFeed answers.
+func (fc *FeedCache) Invalidate() {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.feeds, fc.fetchedAt = nil, time.Time{}
+	fc.generation++
+	clear(fc.unknown)
+}
+
+func (fc *FeedCache) do(req *http.Request, next HttpRequestDoer) (*http.Response, error) {
+	op := operationName(req)
+	if op == OpGetListFeeds {
+		return fc.listFeeds(req, next)
+	}
+	info := requestInfo{Operation: op}
+	if unknownFeedOps[op] || op == OpPostRegisterFeed {
+		info = inspectRequest(req)
+	}
+	if unknownFeedOps[op] && fc.knownUnknown(info.Feed) {
+		body := fmt.Sprintf(`{"error":%q,"message":"Feed with URI %s does not exist (cached)."}`, ErrorCodeUnknownFeed, info.Feed)
+		return newResponse(req, http.StatusNotFound, http.Header{"Content-Type": {"application/json"}}, body), nil
+	}
+
+	rsp, err := next.Do(req)
+	if err != nil {
+		return rsp, err
+	}
+	switch {
+	case rsp.StatusCode == http.StatusNotFound && unknownFeedOps[op]:
+		if inspectResponse(op, rsp).ErrorCode == ErrorCodeUnknownFeed {
+			fc.forget(info.Feed)
+		}
+	case rsp.StatusCode == http.StatusConflict && op == OpPostRegisterFeed:
+		fc.exists(info.Feed)
+	case rsp.StatusCode != http.StatusOK:
+	case op == OpPostRegisterFeed || op == OpPostUpdateFeed:
+		var payload struct {
+			Feed FeedSettings `json:"feed"`
+		}
+		if json.Unmarshal(inspectResponse(op, rsp).Body, &payload) == nil && payload.Feed.Uri != "" {
+			fc.store(payload.Feed)
+		} else {
+			fc.Invalidate()
+		}
+	case op == OpPostUnregisterFeed:
+		fc.forget(info.Feed)
+	}
+	return rsp, nil
+}
+
+// listFeeds serves listFeeds from the cache or collapses concurrent misses
+// into one request.
+func (fc *FeedCache) listFeeds(req *http.Request, next HttpRequestDoer) (*http.Response, error) {
+	fc.mu.Lock()
+	if !fc.fetchedAt.IsZero() && fc.opts.Now().Sub(fc.fetchedAt) < fc.opts.TTL {
+		body, err := json.Marshal(struct {
+			Feeds []FeedSettings `json:"feeds"`
+		}{slices.Clone(fc.feeds)})
+		fc.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(req, http.StatusOK, http.Header{"Content-Type": {"application/json"}}, string(body)), nil
+	}
+	if call := fc.inflight; call != nil {
+		fc.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			// The leader's context ended; send our own request.
+			return next.Do(req)
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return newResponse(req, call.status, call.header.Clone(), string(call.body)), nil
+	}
+	call := &feedListCall{done: make(chan struct{})}
+	fc.inflight = call
+	generation := fc.generation
+	fc.mu.Unlock()
+
+	rsp, err := next.Do(req)
+	var info responseInfo
+	if err == nil {
+		info = inspectResponse(OpGetListFeeds, rsp)
+		call.status, call.header, call.body = rsp.StatusCode, rsp.Header.Clone(), info.Body
+		if info.Body == nil {
+			call.err = errors.New("gyoka: getListFeeds: response body unreadable")
+		}
+	} else {
+		call.err = err
+	}
+
+	fc.mu.Lock()
+	fc.inflight = nil
+	if err == nil && rsp.StatusCode == http.StatusOK && fc.generation == generation {
+		var payload struct {
+			Feeds []FeedSettings `json:"feeds"`
+		}
+		if json.Unmarshal(info.Body, &payload) == nil {
+			fc.feeds, fc.fetchedAt = payload.Feeds, fc.opts.Now()
+			for _, f := range payload.Feeds {
+				delete(fc.unknown, f.Uri)
+			}
+		}
+	}
+	fc.mu.Unlock()
+	close(call.done)
+	return rsp, err
+}
+
+// knownUnknown reports whether feed got a 404 UnknownFeed within NegativeTTL.
+func (fc *FeedCache) knownUnknown(feed string) bool {
+	if feed == "" || strings.Contains(feed, ",") {
+		return false
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	expires, ok := fc.unknown[feed]
+	if ok && !fc.opts.Now().Before(expires) {
+		delete(fc.unknown, feed)
+		return false
+	}
+	return ok
+}
+
+// store records f as registered with the given settings.
+func (fc *FeedCache) store(f FeedSettings) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.generation++
+	delete(fc.unknown, f.Uri)
+	if i := slices.IndexFunc(fc.feeds, func(c FeedSettings) bool { return c.Uri == f.Uri }); i >= 0 {
+		fc.feeds[i] = f
+		return
+	}
+	if !fc.fetchedAt.IsZero() {
+		fc.feeds = append(fc.feeds, f)
+	}
+}
+
+// exists records that feed is registered, as a registerFeed 409 proves,
+// with settings the cache does not know. A cached feed list without it is
+// dropped.
+func (fc *FeedCache) exists(feed string) {
+	if feed == "" {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.generation++
+	delete(fc.unknown, feed)
+	if !slices.ContainsFunc(fc.feeds, func(c FeedSettings) bool { return c.Uri == feed }) {
+		fc.feeds, fc.fetchedAt = nil, time.Time{}
+	}
+}
+
+// forget records feed as unknown.
+func (fc *FeedCache) forget(feed string) {
+	if feed == "" || strings.Contains(feed, ",") {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.generation++
+	fc.feeds = slices.DeleteFunc(fc.feeds, func(c FeedSettings) bool { return c.Uri == feed })
+	fc.unknown[feed] = fc.opts.Now().Add(fc.opts.NegativeTTL)
+}
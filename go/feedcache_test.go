@@ -0,0 +1,98 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+// countingHandler counts the requests h serves per path.
+type countingHandler struct {
+	h  http.Handler
+	mu sync.Mutex
+	n  map[string]int
+}
+
+func (c *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.n[r.URL.Path]++
+	c.mu.Unlock()
+	c.h.ServeHTTP(w, r)
+}
+
+func (c *countingHandler) count(path string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n[path]
+}
+
+func TestFeedCacheUnknownFeed(t *testing.T) {
+	ctx := context.Background()
+	counter := &countingHandler{h: server.NewMemoryServer().Handler(), n: make(map[string]int)}
+	srv := httptest.NewServer(counter)
+	t.Cleanup(srv.Close)
+	cached, err := client.NewClientWithResponses(srv.URL, client.WithHTTPClient(srv.Client()), client.WithFeedCache(client.NewFeedCache(client.FeedCacheOptions{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := client.NewClientWithResponses(srv.URL, client.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getPosts := func() int {
+		t.Helper()
+		rsp, err := cached.GetGetPostsWithResponse(ctx, &client.GetGetPostsParams{Feed: testFeed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rsp.StatusCode()
+	}
+	if got := getPosts(); got != http.StatusNotFound {
+		t.Fatalf("getPosts of unregistered feed: status %d, want 404", got)
+	}
+	if got := getPosts(); got != http.StatusNotFound {
+		t.Fatalf("cached getPosts: status %d, want 404", got)
+	}
+	if n := counter.count("/api/feed/getPosts"); n != 1 {
+		t.Errorf("getPosts reached the server %d times, want 1", n)
+	}
+
+	// Another client registers the feed; the cached client learns about it
+	// from the 409 of its own registerFeed.
+	registerFeed(t, plain, testFeed)
+	rsp, err := cached.PostRegisterFeedWithResponse(ctx, client.PostRegisterFeedJSONRequestBody{Uri: testFeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode() != http.StatusConflict {
+		t.Fatalf("second registerFeed: status %d, want 409", rsp.StatusCode())
+	}
+	if got := getPosts(); got != http.StatusOK {
+		t.Errorf("getPosts after 409: status %d, want 200", got)
+	}
+}
+
+func TestFeedCacheListFeeds(t *testing.T) {
+	ctx := context.Background()
+	counter := &countingHandler{h: server.NewMemoryServer().Handler(), n: make(map[string]int)}
+	c := newTestClient(t, counter, client.WithFeedCache(client.NewFeedCache(client.FeedCacheOptions{})))
+	registerFeed(t, c, testFeed)
+	for range 3 {
+		feeds, err := client.ListFeeds(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(feeds) != 1 || feeds[0].Uri != testFeed {
+			t.Fatalf("ListFeeds = %v, want %s", feeds, testFeed)
+		}
+	}
+	if n := counter.count("/api/feed/listFeeds"); n > 1 {
+		t.Errorf("listFeeds reached the server %d times, want at most 1", n)
+	}
+}
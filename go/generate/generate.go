@@ -1,2 +1,4 @@
 //go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen --generate=client,types --config=config.yaml ../../schema/openapi.json
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen --config=server.yaml ../../schema/openapi.json
+//go:generate go run ./schemacheck stamp -schema ../../schema/openapi.json -o ../schema_version.gen.go
 package client
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Severity classifies a schema change from the point of view of a client
+// generated from the old document.
+type Severity string
+
+const (
+	Breaking Severity = "breaking"
+	Additive Severity = "additive"
+)
+
+// Change is one difference between two OpenAPI documents.
+type Change struct {
+	Severity Severity `json:"severity"`
+	// Location identifies the changed element, e.g.
+	// "POST /api/feed/addPost request.post.cid". It is also the key used to
+	// acknowledge breaking changes.
+	Location     string `json:"location"`
+	Detail       string `json:"detail"`
+	Acknowledged bool   `json:"acknowledged,omitempty"`
+}
+
+func (c Change) String() string {
+	s := fmt.Sprintf("%-9s %s: %s", strings.ToUpper(string(c.Severity)), c.Location, c.Detail)
+	if c.Acknowledged {
+		s += " (acknowledged)"
+	}
+	return s
+}
+
+// direction tells the schema differ whether a schema describes data the
+// client sends or data it receives, which decides what counts as breaking.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// differ compares two decoded OpenAPI documents.
+type differ struct {
+	old, new map[string]any
+	changes  []Change
+}
+
+// diffDocuments returns the changes from old to new, sorted by location.
+func diffDocuments(old, new map[string]any) []Change {
+	d := &differ{old: old, new: new}
+	d.diffPaths()
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Location < d.changes[j].Location })
+	return d.changes
+}
+
+func (d *differ) add(sev Severity, loc, format string, args ...any) {
+	d.changes = append(d.changes, Change{Severity: sev, Location: loc, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) diffPaths() {
+	oldPaths, newPaths := object(d.old["paths"]), object(d.new["paths"])
+	for _, path := range unionKeys(oldPaths, newPaths) {
+		op, np := object(oldPaths[path]), object(newPaths[path])
+		switch {
+		case np == nil:
+			d.add(Breaking, path, "path removed")
+			continue
+		case op == nil:
+			d.add(Additive, path, "path added")
+			continue
+		}
+		for _, m := range methods {
+			oo, no := object(op[m]), object(np[m])
+			loc := strings.ToUpper(m) + " " + path
+			switch {
+			case oo == nil && no == nil:
+			case no == nil:
+				d.add(Breaking, loc, "operation removed")
+			case oo == nil:
+				d.add(Additive, loc, "operation added")
+			default:
+				d.diffOperation(loc, oo, no)
+			}
+		}
+	}
+}
+
+func (d *differ) diffOperation(loc string, old, new map[string]any) {
+	if a, b := old["operationId"], new["operationId"]; a != b {
+		d.add(Breaking, loc, "operationId %v -> %v", a, b)
+	}
+	d.diffParameters(loc, list(old["parameters"]), list(new["parameters"]))
+
+	oldBody, newBody := jsonSchema(old["requestBody"]), jsonSchema(new["requestBody"])
+	switch {
+	case oldBody == nil && newBody != nil:
+		d.add(Breaking, loc+" request", "request body added")
+	case oldBody != nil && newBody == nil:
+		d.add(Breaking, loc+" request", "request body removed")
+	case oldBody != nil:
+		d.diffSchema(loc+" request", oldBody, newBody, request)
+	}
+
+	oldResp, newResp := object(old["responses"]), object(new["responses"])
+	for _, status := range unionKeys(oldResp, newResp) {
+		rloc := loc + " response " + status
+		or, nr := object(oldResp[status]), object(newResp[status])
+		switch {
+		case nr == nil:
+			sev := Additive
+			if strings.HasPrefix(status, "2") {
+				sev = Breaking
+			}
+			d.add(sev, rloc, "response removed")
+		case or == nil:
+			d.add(Additive, rloc, "response added")
+		default:
+			oldSchema, newSchema := jsonSchema(or), jsonSchema(nr)
+			if oldSchema != nil && newSchema != nil {
+				d.diffSchema(rloc, oldSchema, newSchema, response)
+			} else if oldSchema != nil {
+				d.add(Breaking, rloc, "JSON body removed")
+			}
+		}
+	}
+}
+
+func (d *differ) diffParameters(loc string, old, new []any) {
+	key := func(p map[string]any) string { return fmt.Sprintf("%v %v", p["in"], p["name"]) }
+	index := func(params []any, doc map[string]any) map[string]map[string]any {
+		m := make(map[string]map[string]any, len(params))
+		for _, p := range params {
+			if obj := d.resolve(object(p), doc); obj != nil {
+				m[key(obj)] = obj
+			}
+		}
+		return m
+	}
+	om, nm := index(old, d.old), index(new, d.new)
+	for _, k := range unionKeys(om, nm) {
+		ploc := loc + " parameter " + k
+		op, np := om[k], nm[k]
+		switch {
+		case np == nil:
+			d.add(Breaking, ploc, "parameter removed")
+		case op == nil && np["required"] == true:
+			d.add(Breaking, ploc, "required parameter added")
+		case op == nil:
+			d.add(Additive, ploc, "optional parameter added")
+		default:
+			if op["required"] != true && np["required"] == true {
+				d.add(Breaking, ploc, "parameter is now required")
+			}
+			if oldSchema, newSchema := object(op["schema"]), object(np["schema"]); oldSchema != nil && newSchema != nil {
+				d.diffSchema(ploc, oldSchema, newSchema, request)
+			}
+		}
+	}
+}
+
+// limits maps schema keywords to whether a larger value is tighter. The
+// exclusive bounds are booleans in OpenAPI 3.0 and numbers in 3.1.
+var limits = map[string]bool{
+	"minLength":        true,
+	"minimum":          true,
+	"exclusiveMinimum": true,
+	"minItems":         true,
+	"maxLength":        false,
+	"maximum":          false,
+	"exclusiveMaximum": false,
+	"maxItems":         false,
+}
+
+// compositions maps the composition keywords to whether an added member
+// narrows the schema, as in allOf, or widens it, as in oneOf and anyOf.
+var compositions = map[string]bool{
+	"allOf": true,
+	"anyOf": false,
+	"oneOf": false,
+}
+
+func (d *differ) diffSchema(loc string, old, new map[string]any, dir direction) {
+	d.diffSchemaDepth(loc, old, new, dir, 0)
+}
+
+func (d *differ) diffSchemaDepth(loc string, old, new map[string]any, dir direction, depth int) {
+	if depth > 32 {
+		return
+	}
+	old, new = d.resolve(old, d.old), d.resolve(new, d.new)
+	if old == nil || new == nil {
+		return
+	}
+
+	if a, b := old["type"], new["type"]; !reflect.DeepEqual(a, b) {
+		d.add(Breaking, loc, "type %v -> %v", a, b)
+		return
+	}
+	if old["deprecated"] != true && new["deprecated"] == true {
+		d.add(Additive, loc, "deprecated")
+	}
+	d.diffNullable(loc, old["nullable"] == true, new["nullable"] == true, dir)
+	d.diffFormat(loc, old["format"], new["format"], dir)
+	d.diffEnum(loc, list(old["enum"]), list(new["enum"]), dir)
+	for _, kw := range sortedKeys(limits) {
+		d.diffLimit(loc, kw, old[kw], new[kw], limits[kw], dir)
+	}
+	for _, kw := range sortedKeys(compositions) {
+		d.diffComposition(loc, kw, list(old[kw]), list(new[kw]), compositions[kw], dir, depth)
+	}
+
+	oldProps, newProps := object(old["properties"]), object(new["properties"])
+	oldReq, newReq := stringSet(old["required"]), stringSet(new["required"])
+	for _, name := range unionKeys(oldProps, newProps) {
+		ploc := loc + "." + name
+		op, np := object(oldProps[name]), object(newProps[name])
+		switch {
+		case np == nil:
+			d.add(Breaking, ploc, "property removed")
+		case op == nil && newReq[name] && dir == request:
+			d.add(Breaking, ploc, "required property added")
+		case op == nil:
+			d.add(Additive, ploc, "property added")
+		default:
+			switch {
+			case !oldReq[name] && newReq[name] && dir == request:
+				d.add(Breaking, ploc, "property is now required")
+			case oldReq[name] && !newReq[name] && dir == response:
+				d.add(Breaking, ploc, "property is no longer required")
+			case oldReq[name] != newReq[name]:
+				d.add(Additive, ploc, "required %t -> %t", oldReq[name], newReq[name])
+			}
+			d.diffSchemaDepth(ploc, op, np, dir, depth+1)
+		}
+	}
+
+	if oi, ni := object(old["items"]), object(new["items"]); oi != nil && ni != nil {
+		d.diffSchemaDepth(loc+"[]", oi, ni, dir, depth+1)
+	}
+}
+
+// diffNullable reports a request schema that no longer accepts null, or a
+// response schema that may now be null, as breaking.
+func (d *differ) diffNullable(loc string, old, new bool, dir direction) {
+	if old == new {
+		return
+	}
+	sev := Additive
+	if (dir == request && old) || (dir == response && new) {
+		sev = Breaking
+	}
+	d.add(sev, loc, "nullable %t -> %t", old, new)
+}
+
+// diffFormat reports a changed format as breaking. An added format only
+// narrows a response and a removed one only widens a request.
+func (d *differ) diffFormat(loc string, old, new any, dir direction) {
+	if old == new {
+		return
+	}
+	sev := Breaking
+	if (old == nil && dir == response) || (new == nil && dir == request) {
+		sev = Additive
+	}
+	d.add(sev, loc, "format %s -> %s", formatLimit(old), formatLimit(new))
+}
+
+// diffComposition compares the members of allOf, anyOf or oneOf by
+// position. A member that narrows a request or widens a response is
+// breaking.
+func (d *differ) diffComposition(loc, kw string, old, new []any, addNarrows bool, dir direction, depth int) {
+	for i := range max(len(old), len(new)) {
+		mloc := fmt.Sprintf("%s.%s[%d]", loc, kw, i)
+		switch {
+		case i >= len(new), i >= len(old):
+			added := i >= len(old)
+			narrows := added == addNarrows
+			sev := Additive
+			if narrows == (dir == request) {
+				sev = Breaking
+			}
+			what := "member removed"
+			if added {
+				what = "member added"
+			}
+			d.add(sev, mloc, "%s", what)
+		default:
+			d.diffSchemaDepth(mloc, object(old[i]), object(new[i]), dir, depth+1)
+		}
+	}
+}
+
+// diffEnum treats removed values as breaking. Added values are breaking in
+// responses, where the client may not handle them, such as a new error code
+// or reason type.
+func (d *differ) diffEnum(loc string, old, new []any, dir direction) {
+	if old == nil && new == nil {
+		return
+	}
+	// A new enum narrows the values a request may send; dropping one lets a
+	// response return values the client does not know.
+	if old == nil {
+		sev := Additive
+		if dir == request {
+			sev = Breaking
+		}
+		d.add(sev, loc, "enum added %v", new)
+		return
+	}
+	if new == nil {
+		sev := Additive
+		if dir == response {
+			sev = Breaking
+		}
+		d.add(sev, loc, "enum removed")
+		return
+	}
+	var removed, added []string
+	for _, v := range old {
+		if !slices.Contains(new, v) {
+			removed = append(removed, fmt.Sprint(v))
+		}
+	}
+	for _, v := range new {
+		if !slices.Contains(old, v) {
+			added = append(added, fmt.Sprint(v))
+		}
+	}
+	if len(removed) > 0 {
+		d.add(Breaking, loc, "enum values removed: %s", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		sev := Additive
+		if dir == response {
+			sev = Breaking
+		}
+		d.add(sev, loc, "enum values added: %s", strings.Join(added, ", "))
+	}
+}
+
+// diffLimit reports tighter request limits as breaking. Limits on responses
+// are informational.
+func (d *differ) diffLimit(loc, kw string, old, new any, largerIsTighter bool, dir direction) {
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	o, oOK := old.(float64)
+	n, nOK := new.(float64)
+	ob, oBool := old.(bool)
+	nb, nBool := new.(bool)
+	var tighter bool
+	switch {
+	case oBool || nBool:
+		// OpenAPI 3.0 exclusive bounds: true is tighter than false or unset.
+		tighter = nb && !ob
+	case !oOK:
+		tighter = true
+	case !nOK:
+		tighter = false
+	case largerIsTighter:
+		tighter = n > o
+	default:
+		tighter = n < o
+	}
+	sev := Additive
+	if tighter && dir == request {
+		sev = Breaking
+	}
+	d.add(sev, loc, "%s %s -> %s", kw, formatLimit(old), formatLimit(new))
+}
+
+func formatLimit(v any) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprint(v)
+}
+
+// resolve follows local $ref pointers of the form #/components/schemas/X.
+func (d *differ) resolve(schema map[string]any, doc map[string]any) map[string]any {
+	for i := 0; i < 16 && schema != nil; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		var node any = doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			node = object(node)[part]
+		}
+		schema = object(node)
+	}
+	return schema
+}
+
+// jsonSchema returns the application/json schema of a request body or response.
+func jsonSchema(v any) map[string]any {
+	content := object(object(v)["content"])
+	for mediaType, mt := range content {
+		if strings.Contains(mediaType, "json") {
+			return object(object(mt)["schema"])
+		}
+	}
+	return nil
+}
+
+func object(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func list(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func stringSet(v any) map[string]bool {
+	set := make(map[string]bool)
+	for _, s := range list(v) {
+		if s, ok := s.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return unionKeys(m, nil)
+}
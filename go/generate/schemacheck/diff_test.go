@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestDiffSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new map[string]any
+		dir      direction
+		want     Severity
+	}{
+		{"nullable dropped from request", map[string]any{"nullable": true}, map[string]any{}, request, Breaking},
+		{"nullable dropped from response", map[string]any{"nullable": true}, map[string]any{"nullable": false}, response, Additive},
+		{"nullable added to response", map[string]any{}, map[string]any{"nullable": true}, response, Breaking},
+		{"exclusiveMinimum set in request", map[string]any{"minimum": 0.0}, map[string]any{"minimum": 0.0, "exclusiveMinimum": true}, request, Breaking},
+		{"exclusiveMaximum raised in request", map[string]any{"exclusiveMaximum": 10.0}, map[string]any{"exclusiveMaximum": 20.0}, request, Additive},
+		{"format changed", map[string]any{"format": "date-time"}, map[string]any{"format": "date"}, response, Breaking},
+		{"format added to response", map[string]any{}, map[string]any{"format": "uri"}, response, Additive},
+		{"format added to request", map[string]any{}, map[string]any{"format": "uri"}, request, Breaking},
+		{"enum value removed from response", map[string]any{"enum": []any{"a", "b"}}, map[string]any{"enum": []any{"a"}}, response, Breaking},
+		{"enum value added to request", map[string]any{"enum": []any{"a"}}, map[string]any{"enum": []any{"a", "b"}}, request, Additive},
+		{"enum dropped from response", map[string]any{"enum": []any{"a"}}, map[string]any{}, response, Breaking},
+		{"enum dropped from request", map[string]any{"enum": []any{"a"}}, map[string]any{}, request, Additive},
+		{"oneOf member added to response", map[string]any{"oneOf": []any{map[string]any{"type": "string"}}}, map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer"}}}, response, Breaking},
+		{"oneOf member added to request", map[string]any{"oneOf": []any{map[string]any{"type": "string"}}}, map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer"}}}, request, Additive},
+		{"allOf member added to request", map[string]any{"allOf": []any{map[string]any{"type": "object"}}}, map[string]any{"allOf": []any{map[string]any{"type": "object"}, map[string]any{"required": []any{"uri"}}}}, request, Breaking},
+		{"allOf member changed", map[string]any{"allOf": []any{map[string]any{"type": "string"}}}, map[string]any{"allOf": []any{map[string]any{"type": "integer"}}}, response, Breaking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &differ{}
+			d.diffSchema("s", tt.old, tt.new, tt.dir)
+			if len(d.changes) != 1 {
+				t.Fatalf("got changes %v, want one", d.changes)
+			}
+			if got := d.changes[0].Severity; got != tt.want {
+				t.Errorf("got %v, want %s", d.changes[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSchemaNullableProperty(t *testing.T) {
+	post := func(nullable bool) map[string]any {
+		return map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"languages": map[string]any{"type": "array", "nullable": nullable}},
+		}
+	}
+	d := &differ{}
+	d.diffSchema("s", post(true), post(false), request)
+	if len(d.changes) != 1 || d.changes[0].Location != "s.languages" || d.changes[0].Severity != Breaking {
+		t.Errorf("got changes %v, want a breaking change at s.languages", d.changes)
+	}
+}
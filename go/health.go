@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HealthSeverity ranks health issues. The zero value is HealthOK.
+type HealthSeverity int
+
+const (
+	HealthOK HealthSeverity = iota
+	HealthWarning
+	HealthCritical
+)
+
+func (s HealthSeverity) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthWarning:
+		return "warning"
+	case HealthCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("HealthSeverity(%d)", int(s))
+}
+
+func (s HealthSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// HealthIssueKind identifies the check that raised a HealthIssue.
+type HealthIssueKind string
+
+const (
+	// HealthIssueInactive: the feed is registered with isActive=false.
+	HealthIssueInactive HealthIssueKind = "inactive"
+	// HealthIssueEmpty: getPosts returned no posts.
+	HealthIssueEmpty HealthIssueKind = "empty"
+	// HealthIssueStale: the newest post is older than HealthChecker.StaleAfter.
+	HealthIssueStale HealthIssueKind = "stale"
+	// HealthIssueMissingLanguages: langFilter is on but sampled posts have no languages.
+	HealthIssueMissingLanguages HealthIssueKind = "missingLanguages"
+	// HealthIssueError: a request for the feed failed.
+	HealthIssueError HealthIssueKind = "error"
+)
+
+// HealthIssue is a single finding of a health check.
+type HealthIssue struct {
+	Kind     HealthIssueKind `json:"kind"`
+	Severity HealthSeverity  `json:"severity"`
+	Message  string          `json:"message"`
+}
+
+// PingHealth is the result of GetPing.
+type PingHealth struct {
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FeedHealth is the result of checking a single feed.
+type FeedHealth struct {
+	Feed       string `json:"feed"`
+	IsActive   bool   `json:"isActive"`
+	LangFilter bool   `json:"langFilter"`
+	// SampledPosts is the number of posts returned by the sampled getPosts call.
+	SampledPosts    int           `json:"sampledPosts"`
+	NewestIndexedAt *time.Time    `json:"newestIndexedAt,omitempty"`
+	Issues          []HealthIssue `json:"issues,omitempty"`
+}
+
+// Severity returns the highest severity among the feed's issues.
+func (f *FeedHealth) Severity() HealthSeverity {
+	s := HealthOK
+	for _, issue := range f.Issues {
+		s = max(s, issue.Severity)
+	}
+	return s
+}
+
+func (f *FeedHealth) addIssue(kind HealthIssueKind, severity HealthSeverity, format string, args ...any) {
+	f.Issues = append(f.Issues, HealthIssue{Kind: kind, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// HealthReport is the result of HealthChecker.Check.
+type HealthReport struct {
+	CheckedAt time.Time  `json:"checkedAt"`
+	Ping      PingHealth `json:"ping"`
+	// ListFeedsError is set when the feed list could not be retrieved.
+	ListFeedsError string         `json:"listFeedsError,omitempty"`
+	Feeds          []FeedHealth   `json:"feeds"`
+	Status         HealthSeverity `json:"status"`
+}
+
+func (r *HealthReport) severity() HealthSeverity {
+	if !r.Ping.OK || r.ListFeedsError != "" {
+		return HealthCritical
+	}
+	s := HealthOK
+	for i := range r.Feeds {
+		s = max(s, r.Feeds[i].Severity())
+	}
+	return s
+}
+
+// ExitCode maps Status to a monitoring plugin style exit code:
+// 0 for ok, 1 for warning and 2 for critical.
+func (r *HealthReport) ExitCode() int {
+	return int(r.Status)
+}
+
+// HealthChecker checks the Gyoka instance and every registered feed.
+type HealthChecker struct {
+	Client ClientWithResponsesInterface
+	// SampleSize is the getPosts limit used per feed. Defaults to 50.
+	SampleSize int
+	// StaleAfter flags active feeds whose newest post is older than this.
+	// Defaults to 24 hours.
+	StaleAfter time.Duration
+	// Concurrency is the number of feeds checked at once. Defaults to 4.
+	Concurrency int
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+const (
+	defaultHealthSampleSize = 50
+	defaultHealthStaleAfter = 24 * time.Hour
+)
+
+// NewHealthChecker creates a HealthChecker with default settings.
+func NewHealthChecker(c ClientWithResponsesInterface) *HealthChecker {
+	return &HealthChecker{Client: c}
+}
+
+func (h *HealthChecker) now() time.Time {
+	if h.Now != nil {
+		return h.Now()
+	}
+	return time.Now()
+}
+
+// Check pings the instance, lists its feeds and samples the posts of each
+// feed, Concurrency feeds at a time. Feeds keep the order of the feed list.
+// Failures are recorded in the report rather than returned.
+func (h *HealthChecker) Check(ctx context.Context) *HealthReport {
+	report := &HealthReport{CheckedAt: h.now()}
+	report.Ping = h.ping(ctx)
+
+	feeds, err := ForEachFeed(ctx, h.Client, FanOutOptions{Concurrency: h.Concurrency}, func(ctx context.Context, f FeedSettings) (FeedHealth, error) {
+		return h.checkFeed(ctx, FeedHealth{Feed: f.Uri, IsActive: f.IsActive, LangFilter: f.LangFilter}, report.CheckedAt), nil
+	})
+	if err != nil {
+		report.ListFeedsError = err.Error()
+	} else {
+		for _, res := range feeds.Results {
+			fh := res.Value
+			if res.Err != nil {
+				fh = FeedHealth{Feed: res.Feed}
+				fh.addIssue(HealthIssueError, HealthCritical, "check failed: %v", res.Err)
+			}
+			report.Feeds = append(report.Feeds, fh)
+		}
+	}
+	report.Status = report.severity()
+	return report
+}
+
+func (h *HealthChecker) ping(ctx context.Context) PingHealth {
+	start := time.Now()
+	resp, err := h.Client.GetPingWithResponse(ctx)
+	ping := PingHealth{LatencyMs: time.Since(start).Milliseconds()}
+	switch {
+	case err != nil:
+		ping.Error = err.Error()
+	case resp.StatusCode() != http.StatusOK:
+		ping.Error = newAPIError(OpGetPing, resp.HTTPResponse, resp.Body).Error()
+	default:
+		ping.OK = true
+	}
+	return ping
+}
+
+func (h *HealthChecker) checkFeed(ctx context.Context, fh FeedHealth, now time.Time) FeedHealth {
+	if !fh.IsActive {
+		fh.addIssue(HealthIssueInactive, HealthWarning, "feed is inactive")
+	}
+	limit := h.SampleSize
+	if limit <= 0 {
+		limit = defaultHealthSampleSize
+	}
+	page, err := GetPosts(ctx, h.Client, GetGetPostsParams{Feed: fh.Feed, Limit: &limit})
+	if err != nil {
+		fh.addIssue(HealthIssueError, HealthCritical, "getPosts failed: %v", err)
+		return fh
+	}
+
+	fh.SampledPosts = len(page.Posts)
+	if len(page.Posts) == 0 {
+		fh.addIssue(HealthIssueEmpty, HealthWarning, "feed has no posts")
+		return fh
+	}
+	missing := 0
+	for _, p := range page.Posts {
+		if fh.NewestIndexedAt == nil || p.IndexedAt.After(*fh.NewestIndexedAt) {
+			indexedAt := p.IndexedAt
+			fh.NewestIndexedAt = &indexedAt
+		}
+		if len(p.Languages) == 0 {
+			missing++
+		}
+	}
+	staleAfter := h.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultHealthStaleAfter
+	}
+	if age := now.Sub(*fh.NewestIndexedAt); fh.IsActive && age > staleAfter {
+		fh.addIssue(HealthIssueStale, HealthWarning, "newest post was indexed %s ago", age.Round(time.Second))
+	}
+	if fh.LangFilter && missing > 0 {
+		fh.addIssue(HealthIssueMissingLanguages, HealthWarning, "%d of %d sampled posts have no languages", missing, len(page.Posts))
+	}
+	return fh
+}
@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+const testFeed = "at://did:plc:owner/app.bsky.feed.generator/test"
+
+// newTestClient serves h with httptest and returns a client for it. opts
+// are applied after WithHTTPClient.
+func newTestClient(t *testing.T, h http.Handler, opts ...client.ClientOption) *client.ClientWithResponses {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c, err := client.NewClientWithResponses(srv.URL, append([]client.ClientOption{client.WithHTTPClient(srv.Client())}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// newMemoryClient returns a client for a new MemoryServer with testFeed
+// registered.
+func newMemoryClient(t *testing.T, opts ...client.ClientOption) (*client.ClientWithResponses, *server.MemoryServer) {
+	t.Helper()
+	ms := server.NewMemoryServer()
+	c := newTestClient(t, ms.Handler(), opts...)
+	registerFeed(t, c, testFeed)
+	return c, ms
+}
+
+func registerFeed(t *testing.T, c client.ClientWithResponsesInterface, feed string) {
+	t.Helper()
+	rsp, err := c.PostRegisterFeedWithResponse(context.Background(), client.PostRegisterFeedJSONRequestBody{Uri: feed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode() != http.StatusOK {
+		t.Fatalf("register %s: status %d: %s", feed, rsp.StatusCode(), rsp.Body)
+	}
+}
+
+// testPosts returns n posts with distinct URIs and indexedAt times.
+func testPosts(n int) []client.Post {
+	posts := make([]client.Post, n)
+	for i := range posts {
+		posts[i] = client.Post{
+			Uri:       testPostURI(i),
+			Cid:       "bafyreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+			IndexedAt: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+			Languages: []string{},
+		}
+	}
+	return posts
+}
+
+func testPostURI(i int) string {
+	return fmt.Sprintf("at://did:plc:author/app.bsky.feed.post/%06d", i)
+}
+
+// feedURIs returns the URIs of every post in feed.
+func feedURIs(t *testing.T, c client.ClientWithResponsesInterface, feed string) map[string]bool {
+	t.Helper()
+	uris := make(map[string]bool)
+	for p, err := range client.AllPosts(context.Background(), c, feed) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		uris[p.Uri] = true
+	}
+	return uris
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// operationPaths maps API paths to operation names.
+var operationPaths = map[string]string{
+	"/api/feed/addPost":            OpPostAddPost,
+	"/api/feed/batchAddPosts":      OpPostBatchAddPosts,
+	"/api/feed/batchRemovePosts":   OpPostBatchRemovePosts,
+	"/api/feed/getPosts":           OpGetGetPosts,
+	"/api/feed/listFeeds":          OpGetListFeeds,
+	"/api/feed/registerFeed":       OpPostRegisterFeed,
+	"/api/feed/removePost":         OpPostRemovePost,
+	"/api/feed/removePostByAuthor": OpPostRemovePostByAuthor,
+	"/api/feed/trimPosts":          OpPostTrimFeed,
+	"/api/feed/unregisterFeed":     OpPostUnregisterFeed,
+	"/api/feed/updateFeed":         OpPostUpdateFeed,
+	"/api/gyoka/ping":              OpGetPing,
+	"/api/gyoka/updateDocument":    OpPostUpdateDocument,
+}
+
+// OperationOf returns the operation name for a request path, or "" for
+// paths that are not part of the API. A base path in front of /api is
+// ignored.
+func OperationOf(path string) string {
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i:]
+	}
+	return operationPaths[path]
+}
+
+// requestInfo is what the instrumentation options record about a request.
+type requestInfo struct {
+	Operation string
+	// Feed is the feed URI the call targets. Batch calls over several feeds
+	// list them separated by commas.
+	Feed string
+	// BatchSize is the number of posts in a batch call.
+	BatchSize int
+	// Body is a copy of the request body.
+	Body []byte
+}
+
+// inspectRequest reads the operation, feed and batch size of req without
+// consuming its body.
+func inspectRequest(req *http.Request) requestInfo {
+	info := requestInfo{Operation: operationName(req)}
+	if req.URL != nil {
+		info.Feed = req.URL.Query().Get("feed")
+	}
+	info.Body = peekRequestBody(req)
+	if len(info.Body) == 0 {
+		return info
+	}
+	var body struct {
+		Feed    string `json:"feed"`
+		Uri     string `json:"uri"`
+		Entries []struct {
+			Feed  string            `json:"feed"`
+			Posts []json.RawMessage `json:"posts"`
+		} `json:"entries"`
+	}
+	if json.Unmarshal(info.Body, &body) != nil {
+		return info
+	}
+	switch {
+	case body.Feed != "":
+		info.Feed = body.Feed
+	case len(body.Entries) > 0:
+		var feeds []string
+		for _, e := range body.Entries {
+			info.BatchSize += len(e.Posts)
+			if !slices.Contains(feeds, e.Feed) {
+				feeds = append(feeds, e.Feed)
+			}
+		}
+		info.Feed = strings.Join(feeds, ",")
+	case body.Uri != "":
+		info.Feed = body.Uri
+	}
+	return info
+}
+
+// operationName returns the operation of req, or its method and path for
+// requests outside the API.
+func operationName(req *http.Request) string {
+	if req.URL == nil {
+		return req.Method
+	}
+	if op := OperationOf(req.URL.Path); op != "" {
+		return op
+	}
+	return req.Method + " " + req.URL.Path
+}
+
+// peekRequestBody returns a copy of the request body, leaving req readable.
+func peekRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err == nil {
+			defer rc.Close()
+			if data, err := io.ReadAll(rc); err == nil {
+				return data
+			}
+		}
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil }
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// responseInfo is what the instrumentation options record about a response.
+type responseInfo struct {
+	StatusCode int
+	// ErrorCode is the Gyoka error code of a non-200 response.
+	ErrorCode string
+	// Items and ItemFailures count the per-post results of batch calls.
+	Items        int
+	ItemFailures int
+	// Body is a copy of the response body.
+	Body []byte
+}
+
+// inspectResponseHead is inspectResponse for instrumentation that must not
+// buffer streamed bodies: it reads the body only for non-2xx responses and
+// batch calls, whose error code and item counts are in the body, and leaves
+// every other body unread.
+func inspectResponseHead(op string, rsp *http.Response) responseInfo {
+	if rsp.StatusCode >= 200 && rsp.StatusCode <= 299 && op != OpPostBatchAddPosts && op != OpPostBatchRemovePosts {
+		return responseInfo{StatusCode: rsp.StatusCode}
+	}
+	return inspectResponse(op, rsp)
+}
+
+// inspectResponse reads rsp's body, replacing it with an unread copy, and
+// extracts the error code or batch item counts.
+func inspectResponse(op string, rsp *http.Response) responseInfo {
+	info := responseInfo{StatusCode: rsp.StatusCode}
+	if rsp.Body == nil {
+		return info
+	}
+	data, err := io.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		// Hand the read error on to the caller after the bytes that were read.
+		rsp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{err}))
+		return info
+	}
+	rsp.Body = io.NopCloser(bytes.NewReader(data))
+	info.Body = data
+	if rsp.StatusCode != http.StatusOK {
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &payload) == nil {
+			info.ErrorCode = payload.Error
+		}
+		return info
+	}
+	if op != OpPostBatchAddPosts && op != OpPostBatchRemovePosts {
+		return info
+	}
+	var payload struct {
+		Results []struct {
+			Results []struct {
+				Status string `json:"status"`
+			} `json:"results"`
+		} `json:"results"`
+	}
+	if json.Unmarshal(data, &payload) != nil {
+		return info
+	}
+	for _, entry := range payload.Results {
+		for _, item := range entry.Results {
+			info.Items++
+			if item.Status == string(BatchItemError) {
+				info.ItemFailures++
+			}
+		}
+	}
+	return info
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RedactedHeaders are the headers whose values are never logged.
+var RedactedHeaders = []string{"X-API-Key", "CF-Access-Client-Id", "CF-Access-Client-Secret"}
+
+const (
+	redacted            = "[REDACTED]"
+	defaultMaxBodyBytes = 1024
+)
+
+// LoggingOptions configures WithLogging and NewLoggingTransport.
+type LoggingOptions struct {
+	// Logger defaults to slog.Default().
+	Logger *slog.Logger
+	// MaxBodyBytes truncates bodies logged at debug level. Defaults to 1024.
+	MaxBodyBytes int
+	// RedactHeaders lists headers to redact in addition to RedactedHeaders.
+	RedactHeaders []string
+}
+
+// WithLogging wraps the client's HttpRequestDoer so every call logs a debug
+// record for the request and a record for the response: info for 2xx, warn
+// for other statuses and error for transport failures. It must come after
+// WithHTTPClient.
+//
+// Headers added further down, e.g. by a custom http.RoundTripper, are not
+// visible to the Doer; use NewLoggingTransport inside that transport to log
+// them. Either way the values of RedactedHeaders are replaced before logging
+// and scrubbed from logged bodies.
+func WithLogging(opts LoggingOptions) ClientOption {
+	return func(c *Client) error {
+		l := newRequestLogger(opts)
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				return l.do(req, next.Do)
+			})
+		})
+		return nil
+	}
+}
+
+// LoggingTransport is an http.RoundTripper that logs like WithLogging.
+type LoggingTransport struct {
+	next   http.RoundTripper
+	logger *requestLogger
+}
+
+// NewLoggingTransport logs the requests sent through next, which defaults to
+// http.DefaultTransport.
+func NewLoggingTransport(next http.RoundTripper, opts LoggingOptions) *LoggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &LoggingTransport{next: next, logger: newRequestLogger(opts)}
+}
+
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request, and reading a body without
+	// GetBody replaces it with a buffered copy.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		req = req.Clone(req.Context())
+	}
+	return t.logger.do(req, t.next.RoundTrip)
+}
+
+type requestLogger struct {
+	logger       *slog.Logger
+	maxBodyBytes int
+	redact       map[string]bool
+}
+
+func newRequestLogger(opts LoggingOptions) *requestLogger {
+	l := &requestLogger{logger: opts.Logger, maxBodyBytes: opts.MaxBodyBytes, redact: make(map[string]bool)}
+	if l.logger == nil {
+		l.logger = slog.Default()
+	}
+	if l.maxBodyBytes <= 0 {
+		l.maxBodyBytes = defaultMaxBodyBytes
+	}
+	for _, h := range slices.Concat(RedactedHeaders, opts.RedactHeaders) {
+		l.redact[http.CanonicalHeaderKey(h)] = true
+	}
+	return l
+}
+
+func (l *requestLogger) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	debug := l.logger.Enabled(ctx, slog.LevelDebug)
+	// Error is the highest level logged, so when it is disabled no record
+	// is emitted and the body need not be read.
+	info := requestInfo{Operation: operationName(req)}
+	if l.logger.Enabled(ctx, slog.LevelError) {
+		info = inspectRequest(req)
+	}
+	secrets := l.secrets(req.Header)
+	attrs := []slog.Attr{slog.String("operation", info.Operation)}
+	if info.Feed != "" {
+		attrs = append(attrs, slog.String("feed", info.Feed))
+	}
+	if info.BatchSize > 0 {
+		attrs = append(attrs, slog.Int("batchSize", info.BatchSize))
+	}
+	if debug {
+		reqAttrs := append(slices.Clip(attrs),
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.Redacted()),
+			slog.Any("headers", l.redactHeaders(req.Header)),
+		)
+		if len(info.Body) > 0 {
+			reqAttrs = append(reqAttrs, slog.String("body", l.body(info.Body, secrets)))
+		}
+		l.logger.LogAttrs(ctx, slog.LevelDebug, "gyoka request", reqAttrs...)
+	}
+
+	start := time.Now()
+	rsp, err := send(req)
+	attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+	if err != nil {
+		attrs = append(attrs, slog.String("error", l.scrub(err.Error(), secrets)))
+		l.logger.LogAttrs(ctx, slog.LevelError, "gyoka request failed", attrs...)
+		return rsp, err
+	}
+
+	level := slog.LevelInfo
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		level = slog.LevelWarn
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return rsp, nil
+	}
+	// Only debug records carry the body; otherwise leave streamed bodies
+	// unread.
+	inspect := inspectResponseHead
+	if debug {
+		inspect = inspectResponse
+	}
+	ri := inspect(info.Operation, rsp)
+	attrs = append(attrs, slog.Int("status", ri.StatusCode))
+	if ri.ErrorCode != "" {
+		attrs = append(attrs, slog.String("errorCode", ri.ErrorCode))
+	}
+	if ri.ItemFailures > 0 {
+		attrs = append(attrs, slog.Int("itemFailures", ri.ItemFailures))
+	}
+	if debug && len(ri.Body) > 0 {
+		attrs = append(attrs, slog.String("body", l.body(ri.Body, secrets)))
+	}
+	l.logger.LogAttrs(ctx, level, "gyoka response", attrs...)
+	return rsp, nil
+}
+
+// secrets returns the values of the redacted headers present in h.
+func (l *requestLogger) secrets(h http.Header) []string {
+	var values []string
+	for key, vs := range h {
+		if l.redact[http.CanonicalHeaderKey(key)] {
+			for _, v := range vs {
+				if v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func (l *requestLogger) redactHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, vs := range h {
+		if l.redact[http.CanonicalHeaderKey(key)] {
+			out[key] = []string{redacted}
+			continue
+		}
+		out[key] = vs
+	}
+	return out
+}
+
+// body returns data as a string truncated to maxBodyBytes with secrets removed.
+func (l *requestLogger) body(data []byte, secrets []string) string {
+	s := l.scrub(string(data), secrets)
+	if len(s) > l.maxBodyBytes {
+		s = s[:l.maxBodyBytes] + "...(truncated)"
+	}
+	return s
+}
+
+func (l *requestLogger) scrub(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
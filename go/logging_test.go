@@ -0,0 +1,65 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestLoggingWarnCarriesRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	c, _ := newMemoryClient(t, client.WithLogging(client.LoggingOptions{Logger: logger}))
+
+	const unknown = "at://did:plc:owner/app.bsky.feed.generator/unknown"
+	rsp, err := c.PostAddPostWithResponse(context.Background(), client.PostAddPostJSONRequestBody{Feed: unknown, Post: testPosts(1)[0].AddPostParam()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode() == http.StatusOK {
+		t.Fatal("addPost to an unknown feed succeeded")
+	}
+	var record struct {
+		Level, Feed string
+		Status      int
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log %q: %v", buf.String(), err)
+	}
+	if record.Level != "WARN" || record.Feed != unknown || record.Status != rsp.StatusCode() {
+		t.Errorf("got record %s, want a warning with the feed and status", buf.String())
+	}
+}
+
+func TestLoggingTransportKeepsCallerRequest(t *testing.T) {
+	srv := httptest.NewServer(server.NewMemoryServer().Handler())
+	t.Cleanup(srv.Close)
+	tr := client.NewLoggingTransport(srv.Client().Transport, client.LoggingOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))})
+
+	body := io.NopCloser(strings.NewReader(`{"uri":"` + testFeed + `"}`))
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/feed/registerFeed", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Errorf("status %d, want 200", rsp.StatusCode)
+	}
+	if req.Body != body || req.GetBody != nil {
+		t.Error("RoundTrip replaced the caller's request body")
+	}
+}
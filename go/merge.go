@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DedupMode selects the key used to detect duplicate posts when merging.
+type DedupMode string
+
+const (
+	DedupByURI DedupMode = "uri"
+	DedupByCID DedupMode = "cid"
+)
+
+// ContextConflict decides which feedContext survives when duplicates of a
+// post carry different contexts.
+type ContextConflict string
+
+const (
+	// ContextKeepFirst keeps the post from the first source that has it.
+	ContextKeepFirst ContextConflict = "first"
+	// ContextKeepNewest keeps the duplicate with the newest indexedAt.
+	ContextKeepNewest ContextConflict = "newest"
+	// ContextDrop keeps the first duplicate but removes its feedContext.
+	ContextDrop ContextConflict = "drop"
+)
+
+// MergeOptions configures MergeFeeds and CloneFeed.
+type MergeOptions struct {
+	// Dedup defaults to DedupByURI.
+	Dedup DedupMode
+	// OnContextConflict defaults to ContextKeepFirst.
+	OnContextConflict ContextConflict
+	// Limit keeps only the newest Limit posts, by indexedAt, of the merged
+	// sources. Zero means no cap.
+	Limit int
+	// BatchSize is the number of posts per batchAddPosts request.
+	BatchSize int
+	// Batcher, if set, sizes the batchAddPosts requests instead of
+	// BatchSize.
+	Batcher *AdaptiveBatcher
+}
+
+// MergePreview describes what a merge would write.
+type MergePreview struct {
+	Target  string   `json:"target"`
+	Sources []string `json:"sources"`
+	// SourcePosts is the number of posts read from all sources.
+	SourcePosts int `json:"sourcePosts"`
+	// Duplicates is the number of source posts dropped by deduplication.
+	Duplicates int `json:"duplicates"`
+	// ContextConflicts counts duplicates whose feedContext differed.
+	ContextConflicts int `json:"contextConflicts"`
+	// Capped is the number of posts dropped by Limit.
+	Capped int `json:"capped"`
+	// AlreadyInTarget is the number of posts the target already holds.
+	AlreadyInTarget int `json:"alreadyInTarget"`
+	// ToWrite is the number of posts that would be sent to batchAddPosts.
+	ToWrite int `json:"toWrite"`
+	// TargetPosts is the number of posts in the target before the merge.
+	TargetPosts int `json:"targetPosts"`
+	// ResultingSize is the expected number of posts in the target after the merge.
+	ResultingSize int `json:"resultingSize"`
+}
+
+// MergeResult is the outcome of MergeFeeds.
+type MergeResult struct {
+	MergePreview
+	Added    int               `json:"added"`
+	Failures []BatchItemResult `json:"failures,omitempty"`
+}
+
+// PreviewMerge reads the sources and target and reports what MergeFeeds
+// would write, without writing anything.
+func PreviewMerge(ctx context.Context, c ClientWithResponsesInterface, target string, sources []string, opts MergeOptions) (*MergePreview, error) {
+	preview, _, err := planMerge(ctx, c, target, sources, opts)
+	return preview, err
+}
+
+// MergeFeeds copies the posts of sources into target through batchAddPosts,
+// removing duplicates and posts the target already holds.
+func MergeFeeds(ctx context.Context, c ClientWithResponsesInterface, target string, sources []string, opts MergeOptions) (*MergeResult, error) {
+	preview, posts, err := planMerge(ctx, c, target, sources, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := &MergeResult{MergePreview: *preview}
+	items, err := batcherOr(opts.Batcher, opts.BatchSize).AddPosts(ctx, c, target, posts)
+	res.Added, res.Failures = countOK(items)
+	return res, err
+}
+
+// CloneFeed copies the posts of source into target. It is MergeFeeds with a
+// single source.
+func CloneFeed(ctx context.Context, c ClientWithResponsesInterface, source, target string, opts MergeOptions) (*MergeResult, error) {
+	return MergeFeeds(ctx, c, target, []string{source}, opts)
+}
+
+func planMerge(ctx context.Context, c ClientWithResponsesInterface, target string, sources []string, opts MergeOptions) (*MergePreview, []Post, error) {
+	if len(sources) == 0 {
+		return nil, nil, fmt.Errorf("merge: no source feeds")
+	}
+	if slices.Contains(sources, target) {
+		return nil, nil, fmt.Errorf("merge: target %s is also a source", target)
+	}
+	dedup := opts.Dedup
+	if dedup == "" {
+		dedup = DedupByURI
+	}
+	key := func(p Post) string {
+		if dedup == DedupByCID {
+			return p.Cid
+		}
+		return p.Uri
+	}
+
+	preview := &MergePreview{Target: target, Sources: sources}
+	var merged []Post
+	index := make(map[string]int)
+	for _, src := range sources {
+		err := walkPostPages(ctx, c, src, func(page *PostsPage) error {
+			for _, p := range page.Posts {
+				preview.SourcePosts++
+				i, dup := index[key(p)]
+				if !dup {
+					index[key(p)] = len(merged)
+					merged = append(merged, p)
+					continue
+				}
+				preview.Duplicates++
+				if !equalStringPtr(merged[i].FeedContext, p.FeedContext) {
+					preview.ContextConflicts++
+					merged[i] = resolveContextConflict(merged[i], p, opts.OnContextConflict)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("merge: read %s: %w", src, err)
+		}
+	}
+
+	if opts.Limit > 0 && len(merged) > opts.Limit {
+		slices.SortStableFunc(merged, func(a, b Post) int { return b.IndexedAt.Compare(a.IndexedAt) })
+		preview.Capped = len(merged) - opts.Limit
+		merged = merged[:opts.Limit]
+	}
+
+	existing := make(map[string]bool)
+	err := walkPostPages(ctx, c, target, func(page *PostsPage) error {
+		for _, p := range page.Posts {
+			existing[key(p)] = true
+			preview.TargetPosts++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge: read target %s: %w", target, err)
+	}
+	posts := merged[:0]
+	for _, p := range merged {
+		if existing[key(p)] {
+			preview.AlreadyInTarget++
+			continue
+		}
+		posts = append(posts, p)
+	}
+	preview.ToWrite = len(posts)
+	preview.ResultingSize = preview.TargetPosts + preview.ToWrite
+	return preview, posts, nil
+}
+
+func resolveContextConflict(kept, dup Post, mode ContextConflict) Post {
+	switch mode {
+	case ContextKeepNewest:
+		if dup.IndexedAt.After(kept.IndexedAt) {
+			return dup
+		}
+	case ContextDrop:
+		kept.FeedContext = nil
+	}
+	return kept
+}
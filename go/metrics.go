@@ -0,0 +1,241 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from WithMetrics. Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	// RequestStarted is called before a request is sent.
+	RequestStarted(op string)
+	// RequestFinished is called once per started request, after the response
+	// headers arrived or the request failed. The body of a non-2xx response
+	// or a batch call is read first for its error code and item counts;
+	// other bodies are left to the caller.
+	RequestFinished(m RequestMetrics)
+}
+
+// RequestMetrics describes one finished call.
+type RequestMetrics struct {
+	Operation string
+	// StatusCode is 0 when the request failed without a response.
+	StatusCode int
+	// ErrorCode is the Gyoka error code of a non-200 response.
+	ErrorCode string
+	// Latency is the time until the response headers arrived.
+	Latency time.Duration
+	// BatchSize is the number of posts sent by a batch call.
+	BatchSize int
+	// ItemFailures is the number of posts a batch call reported as errors.
+	ItemFailures int
+	// Err is the transport error, if any.
+	Err error
+}
+
+// WithMetrics wraps the client's HttpRequestDoer so every call is reported
+// to m. It must come after WithHTTPClient.
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) error {
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				info := inspectRequest(req)
+				m.RequestStarted(info.Operation)
+				start := time.Now()
+				rsp, err := next.Do(req)
+				rm := RequestMetrics{Operation: info.Operation, BatchSize: info.BatchSize, Latency: time.Since(start), Err: err}
+				if err == nil {
+					ri := inspectResponseHead(info.Operation, rsp)
+					rm.StatusCode, rm.ErrorCode, rm.ItemFailures = ri.StatusCode, ri.ErrorCode, ri.ItemFailures
+				}
+				m.RequestFinished(rm)
+				return rsp, err
+			})
+		})
+		return nil
+	}
+}
+
+// Metric names exported by PrometheusMetrics. They are part of the public
+// interface and do not change between releases.
+const (
+	// MetricRequestsTotal counts finished requests by operation, status and
+	// error_code. Failed requests without a response have status "error".
+	MetricRequestsTotal = "gyoka_client_requests_total"
+	// MetricRequestDuration is a histogram of the time to response headers
+	// in seconds by operation.
+	MetricRequestDuration = "gyoka_client_request_duration_seconds"
+	// MetricInFlight is a gauge of requests in progress by operation.
+	MetricInFlight = "gyoka_client_in_flight_requests"
+	// MetricBatchSize is a gauge of the number of posts in the last batch
+	// request by operation.
+	MetricBatchSize = "gyoka_client_batch_size"
+	// MetricBatchItemFailures counts posts that batch requests reported as
+	// errors, by operation.
+	MetricBatchItemFailures = "gyoka_client_batch_item_failures_total"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the
+// MetricRequestDuration histogram.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is a Metrics implementation that serves the collected
+// metrics in the Prometheus text exposition format.
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu           sync.Mutex
+	requests     map[requestKey]uint64
+	durations    map[string]*histogram
+	inFlight     map[string]int64
+	batchSize    map[string]int
+	itemFailures map[string]uint64
+}
+
+type requestKey struct {
+	op, status, errorCode string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+var _ Metrics = (*PrometheusMetrics)(nil)
+
+// NewPrometheusMetrics creates a PrometheusMetrics with DefaultLatencyBuckets.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		buckets:      DefaultLatencyBuckets,
+		requests:     make(map[requestKey]uint64),
+		durations:    make(map[string]*histogram),
+		inFlight:     make(map[string]int64),
+		batchSize:    make(map[string]int),
+		itemFailures: make(map[string]uint64),
+	}
+}
+
+func (p *PrometheusMetrics) RequestStarted(op string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[op]++
+}
+
+func (p *PrometheusMetrics) RequestFinished(m RequestMetrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[m.Operation]--
+	status := "error"
+	if m.Err == nil {
+		status = strconv.Itoa(m.StatusCode)
+	}
+	p.requests[requestKey{m.Operation, status, m.ErrorCode}]++
+
+	h := p.durations[m.Operation]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[m.Operation] = h
+	}
+	secs := m.Latency.Seconds()
+	for i, le := range p.buckets {
+		if secs <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += secs
+
+	if m.Operation == OpPostBatchAddPosts || m.Operation == OpPostBatchRemovePosts {
+		p.batchSize[m.Operation] = m.BatchSize
+		p.itemFailures[m.Operation] += uint64(m.ItemFailures)
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b strings.Builder
+
+	header(&b, MetricRequestsTotal, "counter", "Finished Gyoka API requests.")
+	keys := make([]requestKey, 0, len(p.requests))
+	for k := range p.requests {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		return strings.Compare(a.op+"\x00"+a.status+"\x00"+a.errorCode, b.op+"\x00"+b.status+"\x00"+b.errorCode)
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s{operation=%s,status=%s,error_code=%s} %d\n", MetricRequestsTotal, quote(k.op), quote(k.status), quote(k.errorCode), p.requests[k])
+	}
+
+	header(&b, MetricRequestDuration, "histogram", "Gyoka API request latency in seconds.")
+	for _, op := range sortedKeys(p.durations) {
+		h := p.durations[op]
+		for i, le := range p.buckets {
+			fmt.Fprintf(&b, "%s_bucket{operation=%s,le=%s} %d\n", MetricRequestDuration, quote(op), quote(strconv.FormatFloat(le, 'g', -1, 64)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket{operation=%s,le=\"+Inf\"} %d\n", MetricRequestDuration, quote(op), h.count)
+		fmt.Fprintf(&b, "%s_sum{operation=%s} %s\n", MetricRequestDuration, quote(op), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "%s_count{operation=%s} %d\n", MetricRequestDuration, quote(op), h.count)
+	}
+
+	header(&b, MetricInFlight, "gauge", "Gyoka API requests in progress.")
+	for _, op := range sortedKeys(p.inFlight) {
+		fmt.Fprintf(&b, "%s{operation=%s} %d\n", MetricInFlight, quote(op), p.inFlight[op])
+	}
+
+	header(&b, MetricBatchSize, "gauge", "Posts in the last batch request.")
+	for _, op := range sortedKeys(p.batchSize) {
+		fmt.Fprintf(&b, "%s{operation=%s} %d\n", MetricBatchSize, quote(op), p.batchSize[op])
+	}
+
+	header(&b, MetricBatchItemFailures, "counter", "Posts reported as errors by batch requests.")
+	for _, op := range sortedKeys(p.itemFailures) {
+		fmt.Fprintf(&b, "%s{operation=%s} %d\n", MetricBatchItemFailures, quote(op), p.itemFailures[op])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = p.WriteTo(w)
+}
+
+// ListenAndServe serves the metrics on addr at /metrics until the server fails.
+func (p *PrometheusMetrics) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+func header(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// quote returns s as a Prometheus label value.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,410 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ConflictStrategy decides what Migrate does when a feed is already
+// registered on the destination (registerFeed answers 409).
+type ConflictStrategy string
+
+const (
+	// ConflictMerge keeps the destination's feed settings and copies the posts.
+	ConflictMerge ConflictStrategy = "merge"
+	// ConflictOverwrite applies the source's feed settings with updateFeed and copies the posts.
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictSkip leaves the feed on the destination untouched.
+	ConflictSkip ConflictStrategy = "skip"
+	// ConflictFail stops the migration with an error.
+	ConflictFail ConflictStrategy = "fail"
+)
+
+// ParseConflictStrategy parses the name of a ConflictStrategy.
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch cs := ConflictStrategy(s); cs {
+	case ConflictMerge, ConflictOverwrite, ConflictSkip, ConflictFail:
+		return cs, nil
+	}
+	return "", fmt.Errorf("unknown conflict strategy %q", s)
+}
+
+// MigrateOptions configures Migrate.
+type MigrateOptions struct {
+	// Feeds limits the migration to these feed URIs. All source feeds are
+	// migrated when empty.
+	Feeds []string
+	// OnConflict defaults to ConflictMerge.
+	OnConflict ConflictStrategy
+	// BatchSize is the number of posts per batchAddPosts request.
+	BatchSize int
+	// Batcher, if set, sizes the batchAddPosts requests instead of
+	// BatchSize.
+	Batcher *AdaptiveBatcher
+	// StatePath, if set, is a file where progress is checkpointed after every
+	// page. Running Migrate again with the same StatePath resumes from it
+	// and sends the posts the destination rejected again.
+	StatePath string
+	// SourceURL and DestURL are the base URLs of src and dst. They are
+	// stored in the checkpoint, and Migrate refuses to resume a checkpoint
+	// written for other instances.
+	SourceURL, DestURL string
+	// Verify compares source and destination after each feed is copied.
+	Verify bool
+	// Progress, if set, is called after every copied page.
+	Progress func(feed string, copied int)
+}
+
+// FeedMigration is the outcome of migrating a single feed.
+type FeedMigration struct {
+	Feed string `json:"feed"`
+	// Registered is true when the feed was newly registered on the destination.
+	Registered bool `json:"registered"`
+	// Conflict is true when the feed already existed on the destination.
+	Conflict bool `json:"conflict"`
+	// SettingsUpdated is true when ConflictOverwrite applied the source settings.
+	SettingsUpdated bool `json:"settingsUpdated"`
+	Skipped         bool `json:"skipped"`
+	// Resumed is true when copying continued from a checkpoint.
+	Resumed  bool              `json:"resumed"`
+	Copied   int               `json:"copied"`
+	Failures []BatchItemResult `json:"failures,omitempty"`
+	Verify   *VerifyResult     `json:"verify,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// MigrateReport is the result of Migrate.
+type MigrateReport struct {
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+	Feeds      []FeedMigration `json:"feeds"`
+}
+
+// OK reports whether every feed was migrated without errors, item
+// failures or verification differences.
+func (r *MigrateReport) OK() bool {
+	for _, f := range r.Feeds {
+		if f.Error != "" || len(f.Failures) > 0 || (f.Verify != nil && !f.Verify.OK()) {
+			return false
+		}
+	}
+	return true
+}
+
+// migrateState is the checkpoint file written to MigrateOptions.StatePath.
+type migrateState struct {
+	Source string                       `json:"source,omitempty"`
+	Dest   string                       `json:"dest,omitempty"`
+	Feeds  map[string]*feedMigrateState `json:"feeds"`
+}
+
+type feedMigrateState struct {
+	Registered bool    `json:"registered"`
+	Cursor     *string `json:"cursor,omitempty"`
+	Copied     int     `json:"copied"`
+	Done       bool    `json:"done"`
+	// Failed holds the posts of checkpointed pages that the destination
+	// rejected. They are sent again on resume.
+	Failed []Post `json:"failed,omitempty"`
+}
+
+func loadMigrateState(path string) (*migrateState, error) {
+	state := &migrateState{Feeds: make(map[string]*feedMigrateState)}
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("read migration state %s: %w", path, err)
+	}
+	if state.Feeds == nil {
+		state.Feeds = make(map[string]*feedMigrateState)
+	}
+	return state, nil
+}
+
+func (s *migrateState) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Migrate copies feed registrations (isActive, langFilter) and all posts from
+// src to dst. Posts keep their indexedAt, cid, languages, feedContext and
+// reason. A failing feed is recorded in the report and does not stop the
+// others; the returned error is reserved for failures that affect the whole
+// run, such as listing the source feeds or ConflictFail.
+func Migrate(ctx context.Context, src, dst ClientWithResponsesInterface, opts MigrateOptions) (*MigrateReport, error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictMerge
+	}
+	report := &MigrateReport{StartedAt: time.Now()}
+	defer func() { report.FinishedAt = time.Now() }()
+
+	feeds, err := ListFeeds(ctx, src)
+	if err != nil {
+		return report, err
+	}
+	if len(opts.Feeds) > 0 {
+		wanted := make(map[string]bool, len(opts.Feeds))
+		for _, uri := range opts.Feeds {
+			wanted[uri] = true
+		}
+		var selected []FeedSettings
+		for _, f := range feeds {
+			if wanted[f.Uri] {
+				selected = append(selected, f)
+				delete(wanted, f.Uri)
+			}
+		}
+		for uri := range wanted {
+			return report, fmt.Errorf("migrate: feed %s is not registered on the source", uri)
+		}
+		feeds = selected
+	}
+
+	state, err := loadMigrateState(opts.StatePath)
+	if err != nil {
+		return report, err
+	}
+	source, dest := strings.TrimRight(opts.SourceURL, "/"), strings.TrimRight(opts.DestURL, "/")
+	if state.Source != source || state.Dest != dest {
+		// Checkpoints written before the URLs were recorded are adopted.
+		if len(state.Feeds) > 0 && (state.Source != "" || state.Dest != "") {
+			return report, fmt.Errorf("migrate: %s is a checkpoint of %s -> %s, not %s -> %s", opts.StatePath, state.Source, state.Dest, source, dest)
+		}
+		state.Source, state.Dest = source, dest
+	}
+	opts.Batcher = batcherOr(opts.Batcher, opts.BatchSize)
+	for _, f := range feeds {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		fm, err := migrateFeed(ctx, src, dst, f, opts, state)
+		if err != nil {
+			fm.Error = err.Error()
+		}
+		report.Feeds = append(report.Feeds, fm)
+		var conflict *migrateConflictError
+		if errors.As(err, &conflict) {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+type migrateConflictError struct{ feed string }
+
+func (e *migrateConflictError) Error() string {
+	return fmt.Sprintf("migrate: feed %s already exists on the destination", e.feed)
+}
+
+func migrateFeed(ctx context.Context, src, dst ClientWithResponsesInterface, f FeedSettings, opts MigrateOptions, state *migrateState) (FeedMigration, error) {
+	fm := FeedMigration{Feed: f.Uri}
+	fs := state.Feeds[f.Uri]
+	if fs == nil {
+		fs = &feedMigrateState{}
+		state.Feeds[f.Uri] = fs
+	}
+
+	if len(fs.Failed) > 0 {
+		fm.Resumed = true
+		items, err := opts.Batcher.AddPosts(ctx, dst, f.Uri, fs.Failed)
+		added, failed := countOK(items)
+		fm.Failures = append(fm.Failures, failed...)
+		if err != nil {
+			fm.Copied = fs.Copied
+			return fm, err
+		}
+		fs.Failed, fs.Copied = failedPosts(fs.Failed, failed), fs.Copied+added
+		if err := state.save(opts.StatePath); err != nil {
+			return fm, err
+		}
+	}
+
+	if fs.Done {
+		fm.Resumed, fm.Copied = true, fs.Copied
+	} else {
+		if !fs.Registered {
+			if err := registerForMigration(ctx, dst, f, opts.OnConflict, &fm); err != nil || fm.Skipped {
+				return fm, err
+			}
+			fs.Registered = true
+			if err := state.save(opts.StatePath); err != nil {
+				return fm, err
+			}
+		}
+		fm.Resumed = fm.Resumed || fs.Cursor != nil
+		err := walkPostPagesFrom(ctx, src, f.Uri, fs.Cursor, func(page *PostsPage) error {
+			items, err := opts.Batcher.AddPosts(ctx, dst, f.Uri, page.Posts)
+			added, failed := countOK(items)
+			fm.Failures = append(fm.Failures, failed...)
+			if err != nil {
+				return err
+			}
+			// The page counts only once it is checkpointed; a page that
+			// failed is copied again on resume. Its rejected posts are kept
+			// in the checkpoint to be retried.
+			fs.Cursor, fs.Copied = page.Cursor, fs.Copied+added
+			fs.Failed = append(fs.Failed, failedPosts(page.Posts, failed)...)
+			if err := state.save(opts.StatePath); err != nil {
+				return err
+			}
+			if opts.Progress != nil {
+				opts.Progress(f.Uri, fs.Copied)
+			}
+			return nil
+		})
+		fm.Copied = fs.Copied
+		if err != nil {
+			return fm, err
+		}
+		fs.Done, fs.Cursor = true, nil
+		if err := state.save(opts.StatePath); err != nil {
+			return fm, err
+		}
+	}
+
+	if opts.Verify {
+		v, err := VerifyMigration(ctx, src, dst, f.Uri)
+		if err != nil {
+			return fm, fmt.Errorf("verify: %w", err)
+		}
+		fm.Verify = v
+	}
+	return fm, nil
+}
+
+// failedPosts returns the posts whose URI is among the failed items.
+func failedPosts(posts []Post, failed []BatchItemResult) []Post {
+	uris := make(map[string]bool, len(failed))
+	for _, item := range failed {
+		uris[item.Uri] = true
+	}
+	var out []Post
+	for _, p := range posts {
+		if uris[p.Uri] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// registerForMigration registers f on dst and applies strategy on conflict.
+func registerForMigration(ctx context.Context, dst ClientWithResponsesInterface, f FeedSettings, strategy ConflictStrategy, fm *FeedMigration) error {
+	resp, err := dst.PostRegisterFeedWithResponse(ctx, f.RegisterFeedBody())
+	if err != nil {
+		return err
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		fm.Registered = true
+		return nil
+	case http.StatusConflict:
+		fm.Conflict = true
+	default:
+		return newAPIError(OpPostRegisterFeed, resp.HTTPResponse, resp.Body)
+	}
+
+	switch strategy {
+	case ConflictSkip:
+		fm.Skipped = true
+		return nil
+	case ConflictFail:
+		return &migrateConflictError{feed: f.Uri}
+	case ConflictOverwrite:
+		resp, err := dst.PostUpdateFeedWithResponse(ctx, f.UpdateFeedBody())
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return newAPIError(OpPostUpdateFeed, resp.HTTPResponse, resp.Body)
+		}
+		fm.SettingsUpdated = true
+	}
+	return nil
+}
+
+// VerifyResult compares the posts of a feed on two instances.
+type VerifyResult struct {
+	SourcePosts      int `json:"sourcePosts"`
+	DestinationPosts int `json:"destinationPosts"`
+	// Missing lists post URIs present on the source but not the destination.
+	Missing []string `json:"missing,omitempty"`
+	// Mismatched lists post URIs whose cid, indexedAt, languages,
+	// feedContext or reason differ.
+	Mismatched []string `json:"mismatched,omitempty"`
+	// Extra lists post URIs present only on the destination.
+	Extra []string `json:"extra,omitempty"`
+}
+
+// OK reports whether every source post exists unchanged on the destination.
+// Extra destination posts are not considered a failure.
+func (v *VerifyResult) OK() bool {
+	return len(v.Missing) == 0 && len(v.Mismatched) == 0
+}
+
+// VerifyMigration reads feed from both clients and compares the posts.
+func VerifyMigration(ctx context.Context, src, dst ClientWithResponsesInterface, feed string) (*VerifyResult, error) {
+	srcPosts, err := collectPostsByURI(ctx, src, feed)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	dstPosts, err := collectPostsByURI(ctx, dst, feed)
+	if err != nil {
+		return nil, fmt.Errorf("destination: %w", err)
+	}
+	v := &VerifyResult{SourcePosts: len(srcPosts), DestinationPosts: len(dstPosts)}
+	for uri, sp := range srcPosts {
+		dp, ok := dstPosts[uri]
+		switch {
+		case !ok:
+			v.Missing = append(v.Missing, uri)
+		case !sp.Equal(dp):
+			v.Mismatched = append(v.Mismatched, uri)
+		}
+	}
+	for uri := range dstPosts {
+		if _, ok := srcPosts[uri]; !ok {
+			v.Extra = append(v.Extra, uri)
+		}
+	}
+	slices.Sort(v.Missing)
+	slices.Sort(v.Mismatched)
+	slices.Sort(v.Extra)
+	return v, nil
+}
+
+func collectPostsByURI(ctx context.Context, c ClientWithResponsesInterface, feed string) (map[string]Post, error) {
+	posts, err := collectPosts(ctx, c, feed)
+	if err != nil {
+		return nil, err
+	}
+	byURI := make(map[string]Post, len(posts))
+	for _, p := range posts {
+		byURI[p.Uri] = p
+	}
+	return byURI, nil
+}
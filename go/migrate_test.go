@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+	"github.com/nus25/gyoka-client/go/server"
+)
+
+func TestMigrateResumeRetriesRejectedPosts(t *testing.T) {
+	ctx := context.Background()
+	src, _ := newMemoryClient(t)
+	if _, err := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{}).AddPosts(ctx, src, testFeed, testPosts(250)); err != nil {
+		t.Fatal(err)
+	}
+
+	// While reject is set, the destination rejects three posts as items by
+	// corrupting their cid on the way.
+	rejected := map[string]bool{testPostURI(3): true, testPostURI(120): true, testPostURI(249): true}
+	reject := true
+	ms := server.NewMemoryServer()
+	handler := ms.Handler()
+	dst := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reject && strings.HasSuffix(r.URL.Path, "/batchAddPosts") {
+			var body client.PostBatchAddPostsJSONRequestBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			for _, e := range body.Entries {
+				for i := range e.Posts {
+					if rejected[e.Posts[i].Uri] {
+						e.Posts[i].Cid = "bad"
+					}
+				}
+			}
+			data, _ := json.Marshal(body)
+			r.Body, r.ContentLength = io.NopCloser(bytes.NewReader(data)), int64(len(data))
+		}
+		handler.ServeHTTP(w, r)
+	}))
+
+	opts := client.MigrateOptions{StatePath: filepath.Join(t.TempDir(), "state.json"), SourceURL: "http://src.example", DestURL: "http://dst.example", BatchSize: 50}
+	report, err := client.Migrate(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm := report.Feeds[0]
+	if report.OK() || len(fm.Failures) != len(rejected) || fm.Copied != 250-len(rejected) {
+		t.Fatalf("first run: ok=%v copied=%d failures=%v", report.OK(), fm.Copied, fm.Failures)
+	}
+
+	reject = false
+	report, err = client.Migrate(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm = report.Feeds[0]
+	if !report.OK() || !fm.Resumed || fm.Copied != 250 {
+		t.Fatalf("resume: ok=%v resumed=%v copied=%d failures=%v", report.OK(), fm.Resumed, fm.Copied, fm.Failures)
+	}
+	stored := feedURIs(t, dst, testFeed)
+	for uri := range rejected {
+		if !stored[uri] {
+			t.Errorf("rejected post %s was not copied on resume", uri)
+		}
+	}
+	if len(stored) != 250 {
+		t.Errorf("destination holds %d posts, want 250", len(stored))
+	}
+
+	opts.DestURL = "http://other.example"
+	if _, err := client.Migrate(ctx, src, dst, opts); err == nil {
+		t.Error("Migrate resumed a checkpoint of another destination")
+	}
+}
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// The generated code describes posts, reasons, feed settings and batch
+// results as anonymous structs that differ per operation. The named types
+// below are shared by every operation and convert to and from the generated
+// request and response types.
+
+// ReasonType is the $type of a skeleton reason.
+type ReasonType string
+
+const (
+	ReasonTypeRepost ReasonType = "app.bsky.feed.defs#skeletonReasonRepost"
+	ReasonTypePin    ReasonType = "app.bsky.feed.defs#skeletonReasonPin"
+)
+
+// Reason is the reason for including a post in the feed skeleton.
+type Reason struct {
+	Type ReasonType `json:"$type"`
+	// Repost is the repost URI for ReasonTypeRepost.
+	Repost *string `json:"repost,omitempty"`
+}
+
+// AddPostReasonParam converts r for use in PostAddPost.
+func (r Reason) AddPostReasonParam() AddPostReasonParam {
+	return AddPostReasonParam{Type: AddPostReasonParamType(r.Type), Repost: r.Repost}
+}
+
+// BatchAddPostReasonParam converts r for use in PostBatchAddPosts.
+func (r Reason) BatchAddPostReasonParam() BatchAddPostReasonParam {
+	return BatchAddPostReasonParam{Type: BatchAddPostReasonParamType(r.Type), Repost: r.Repost}
+}
+
+func (r *Reason) equal(o *Reason) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return r.Type == o.Type && equalStringPtr(r.Repost, o.Repost)
+}
+
+// Post is a post as stored in a feed.
+type Post struct {
+	Uri         string    `json:"uri"`
+	Cid         string    `json:"cid"`
+	IndexedAt   time.Time `json:"indexedAt"`
+	Languages   []string  `json:"languages"`
+	FeedContext *string   `json:"feedContext,omitempty"`
+	Reason      *Reason   `json:"reason,omitempty"`
+}
+
+// Author returns the DID of the repository the post belongs to.
+func (p Post) Author() string {
+	return postAuthor(p.Uri)
+}
+
+// Equal reports whether p and o have the same uri, cid, indexedAt,
+// languages, feedContext and reason.
+func (p Post) Equal(o Post) bool {
+	return p.Uri == o.Uri && p.Cid == o.Cid && p.IndexedAt.Equal(o.IndexedAt) &&
+		slices.Equal(p.Languages, o.Languages) && equalStringPtr(p.FeedContext, o.FeedContext) &&
+		p.Reason.equal(o.Reason)
+}
+
+// AddPostParam converts p for use in PostAddPost.
+func (p Post) AddPostParam() AddPostPostParam {
+	param := AddPostPostParam{
+		Uri:         p.Uri,
+		Cid:         p.Cid,
+		IndexedAt:   timePtr(p.IndexedAt),
+		Languages:   &p.Languages,
+		FeedContext: p.FeedContext,
+	}
+	if p.Reason != nil {
+		reason := p.Reason.AddPostReasonParam()
+		param.Reason = &reason
+	}
+	return param
+}
+
+// BatchAddPostParam converts p for use in PostBatchAddPosts.
+func (p Post) BatchAddPostParam() BatchAddPostPostParam {
+	param := BatchAddPostPostParam{
+		Uri:         p.Uri,
+		Cid:         p.Cid,
+		IndexedAt:   timePtr(p.IndexedAt),
+		Languages:   &p.Languages,
+		FeedContext: p.FeedContext,
+	}
+	if p.Reason != nil {
+		reason := p.Reason.BatchAddPostReasonParam()
+		param.Reason = &reason
+	}
+	return param
+}
+
+// RemovePostParam converts p for use in PostRemovePost.
+func (p Post) RemovePostParam() RemovePostPostParam {
+	return RemovePostPostParam{Uri: p.Uri, IndexedAt: timePtr(p.IndexedAt)}
+}
+
+// BatchRemovePostParam converts p for use in PostBatchRemovePosts.
+func (p Post) BatchRemovePostParam() BatchRemovePostPostParam {
+	return BatchRemovePostPostParam{Uri: p.Uri, IndexedAt: timePtr(p.IndexedAt)}
+}
+
+// PostFromAddPostParam converts a PostAddPost parameter into a Post.
+func PostFromAddPostParam(param AddPostPostParam) Post {
+	p := Post{Uri: param.Uri, Cid: param.Cid, FeedContext: param.FeedContext}
+	if param.IndexedAt != nil {
+		p.IndexedAt = *param.IndexedAt
+	}
+	if param.Languages != nil {
+		p.Languages = *param.Languages
+	}
+	if param.Reason != nil {
+		p.Reason = &Reason{Type: ReasonType(param.Reason.Type), Repost: param.Reason.Repost}
+	}
+	return p
+}
+
+// PostFromBatchAddPostParam converts a PostBatchAddPosts parameter into a Post.
+func PostFromBatchAddPostParam(param BatchAddPostPostParam) Post {
+	p := Post{Uri: param.Uri, Cid: param.Cid, FeedContext: param.FeedContext}
+	if param.IndexedAt != nil {
+		p.IndexedAt = *param.IndexedAt
+	}
+	if param.Languages != nil {
+		p.Languages = *param.Languages
+	}
+	if param.Reason != nil {
+		p.Reason = &Reason{Type: ReasonType(param.Reason.Type), Repost: param.Reason.Repost}
+	}
+	return p
+}
+
+// NewBatchAddPostsBody builds a PostBatchAddPosts body adding posts to feed.
+func NewBatchAddPostsBody(feed string, posts []Post) PostBatchAddPostsJSONRequestBody {
+	params := make([]BatchAddPostPostParam, len(posts))
+	for i, p := range posts {
+		params[i] = p.BatchAddPostParam()
+	}
+	return PostBatchAddPostsJSONRequestBody{Entries: BatchAddPostsEntriesParam{{Feed: feed, Posts: params}}}
+}
+
+// NewBatchRemovePostsBody builds a PostBatchRemovePosts body removing posts from feed.
+func NewBatchRemovePostsBody(feed string, posts []Post) PostBatchRemovePostsJSONRequestBody {
+	params := make([]BatchRemovePostPostParam, len(posts))
+	for i, p := range posts {
+		params[i] = p.BatchRemovePostParam()
+	}
+	return PostBatchRemovePostsJSONRequestBody{Entries: BatchRemovePostsEntriesParam{{Feed: feed, Posts: params}}}
+}
+
+// Post returns the post echoed by a successful addPost, or nil.
+func (r *PostAddPostResponse) Post() *Post {
+	if r.JSON200 == nil {
+		return nil
+	}
+	src := r.JSON200.Post
+	p := &Post{Uri: src.Uri, Cid: src.Cid, IndexedAt: src.IndexedAt, Languages: src.Languages, FeedContext: src.FeedContext}
+	if src.Reason != nil {
+		p.Reason = &Reason{Type: ReasonType(src.Reason.Type), Repost: src.Reason.Repost}
+	}
+	return p
+}
+
+// Posts returns the posts of a successful getPosts, normalised the same way
+// as by PostsDecoder. Other responses are returned as *APIError.
+func (r *GetGetPostsResponse) Posts() ([]Post, error) {
+	if r.StatusCode() != http.StatusOK || r.JSON200 == nil {
+		return nil, newAPIError(OpGetGetPosts, r.HTTPResponse, r.Body)
+	}
+	page, err := (&PostsDecoder{}).Decode(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return page.Posts, nil
+}
+
+// PostsPage is one page of getPosts.
+type PostsPage struct {
+	Feed  string `json:"feed"`
+	Posts []Post `json:"posts"`
+	// Cursor fetches the next page; nil on the last page.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// GetPosts fetches one page of posts with a PostsDecoder without hooks.
+// Non-200 responses are returned as *APIError.
+func GetPosts(ctx context.Context, c ClientWithResponsesInterface, params GetGetPostsParams) (*PostsPage, error) {
+	return (&PostsDecoder{}).GetPosts(ctx, c, params)
+}
+
+// FeedSettings is the registration state of a feed.
+type FeedSettings struct {
+	Uri        string `json:"uri"`
+	IsActive   bool   `json:"isActive"`
+	LangFilter bool   `json:"langFilter"`
+}
+
+// RegisterFeedBody builds a PostRegisterFeed body for f.
+func (f FeedSettings) RegisterFeedBody() PostRegisterFeedJSONRequestBody {
+	return PostRegisterFeedJSONRequestBody{Uri: f.Uri, IsActive: &f.IsActive, LangFilter: &f.LangFilter}
+}
+
+// UpdateFeedBody builds a PostUpdateFeed body setting all fields of f.
+func (f FeedSettings) UpdateFeedBody() PostUpdateFeedJSONRequestBody {
+	return PostUpdateFeedJSONRequestBody{Uri: f.Uri, IsActive: &f.IsActive, LangFilter: &f.LangFilter}
+}
+
+// Feeds returns the feeds of a successful listFeeds, or nil.
+func (r *GetListFeedsResponse) Feeds() []FeedSettings {
+	if r.JSON200 == nil {
+		return nil
+	}
+	feeds := make([]FeedSettings, len(r.JSON200.Feeds))
+	for i, f := range r.JSON200.Feeds {
+		feeds[i] = FeedSettings{Uri: f.Uri, IsActive: f.IsActive, LangFilter: f.LangFilter}
+	}
+	return feeds
+}
+
+// Feed returns the registered feed of a successful registerFeed, or nil.
+func (r *PostRegisterFeedResponse) Feed() *FeedSettings {
+	if r.JSON200 == nil {
+		return nil
+	}
+	f := r.JSON200.Feed
+	return &FeedSettings{Uri: f.Uri, IsActive: f.IsActive, LangFilter: f.LangFilter}
+}
+
+// Feed returns the updated feed of a successful updateFeed, or nil.
+func (r *PostUpdateFeedResponse) Feed() *FeedSettings {
+	if r.JSON200 == nil {
+		return nil
+	}
+	f := r.JSON200.Feed
+	return &FeedSettings{Uri: f.Uri, IsActive: f.IsActive, LangFilter: f.LangFilter}
+}
+
+// ListFeeds returns the settings of every registered feed. Non-200
+// responses are returned as *APIError.
+func ListFeeds(ctx context.Context, c ClientWithResponsesInterface) ([]FeedSettings, error) {
+	resp, err := c.GetListFeedsWithResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return nil, newAPIError(OpGetListFeeds, resp.HTTPResponse, resp.Body)
+	}
+	return resp.Feeds(), nil
+}
+
+// BatchItemStatus is the per-item status of a batch add or remove.
+type BatchItemStatus string
+
+const (
+	BatchItemAdded   BatchItemStatus = "added"
+	BatchItemRemoved BatchItemStatus = "removed"
+	BatchItemError   BatchItemStatus = "error"
+)
+
+// BatchItemResult is the result for a single post of a batch request.
+type BatchItemResult struct {
+	Feed   string          `json:"feed"`
+	Uri    string          `json:"uri"`
+	Status BatchItemStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// OK reports whether the item was added or removed.
+func (r BatchItemResult) OK() bool {
+	return r.Status != BatchItemError
+}
+
+// Items returns the per-post results of a successful batchAddPosts, or nil.
+func (r *PostBatchAddPostsResponse) Items() []BatchItemResult {
+	if r.JSON200 == nil {
+		return nil
+	}
+	var items []BatchItemResult
+	for _, entry := range r.JSON200.Results {
+		for _, res := range entry.Results {
+			items = append(items, BatchItemResult{Feed: entry.Feed, Uri: res.Uri, Status: BatchItemStatus(res.Status), Error: derefString(res.Error)})
+		}
+	}
+	return items
+}
+
+// Items returns the per-post results of a successful batchRemovePosts, or nil.
+func (r *PostBatchRemovePostsResponse) Items() []BatchItemResult {
+	if r.JSON200 == nil {
+		return nil
+	}
+	var items []BatchItemResult
+	for _, entry := range r.JSON200.Results {
+		for _, res := range entry.Results {
+			items = append(items, BatchItemResult{Feed: entry.Feed, Uri: res.Uri, Status: BatchItemStatus(res.Status), Error: derefString(res.Error)})
+		}
+	}
+	return items
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
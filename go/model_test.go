@@ -0,0 +1,33 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	client "github.com/nus25/gyoka-client/go"
+)
+
+func TestGetPostsResponsePosts(t *testing.T) {
+	c, _ := newMemoryClient(t)
+	if _, err := client.NewAdaptiveBatcher(client.AdaptiveBatchOptions{}).AddPosts(context.Background(), c, testFeed, testPosts(2)); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := c.GetGetPostsWithResponse(context.Background(), &client.GetGetPostsParams{Feed: testFeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posts, err := rsp.Posts(); err != nil || len(posts) != 2 {
+		t.Errorf("Posts() = %d posts, %v, want 2", len(posts), err)
+	}
+
+	rsp, err = c.GetGetPostsWithResponse(context.Background(), &client.GetGetPostsParams{Feed: testFeed + "-unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var apiErr *client.APIError
+	if posts, err := rsp.Posts(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || posts != nil {
+		t.Errorf("Posts() = %v, %v, want the 404 APIError", posts, err)
+	}
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Workers before languages was introduced only send langs, current workers
+// send both, and some versions encode reason as a bare repost URI or with a
+// $type. PostsDecoder accepts all of these and produces the same Post, so a
+// client keeps working against mixed worker versions during an upgrade.
+
+// LanguageMismatch describes a post whose languages and deprecated langs
+// fields are both set but disagree. Languages wins.
+type LanguageMismatch struct {
+	Feed      string   `json:"feed"`
+	Uri       string   `json:"uri"`
+	Languages []string `json:"languages"`
+	Langs     []string `json:"langs"`
+}
+
+// PostsDecoder decodes getPosts responses into PostsPage.
+type PostsDecoder struct {
+	// OnLanguageMismatch, if set, is called for every post whose languages
+	// and langs disagree.
+	OnLanguageMismatch func(LanguageMismatch)
+}
+
+type rawPostsPage struct {
+	Feed   string    `json:"feed"`
+	Posts  []rawPost `json:"posts"`
+	Cursor *string   `json:"cursor,omitempty"`
+}
+
+type rawPost struct {
+	Uri         string          `json:"uri"`
+	Cid         string          `json:"cid"`
+	IndexedAt   time.Time       `json:"indexedAt"`
+	Languages   []string        `json:"languages"`
+	Langs       []string        `json:"langs"`
+	FeedContext *string         `json:"feedContext"`
+	Reason      json.RawMessage `json:"reason"`
+}
+
+// Decode decodes a getPosts 200 response body.
+func (d *PostsDecoder) Decode(body []byte) (*PostsPage, error) {
+	var raw rawPostsPage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("decode getPosts response: %w", err)
+	}
+	page := &PostsPage{Feed: raw.Feed, Posts: make([]Post, len(raw.Posts)), Cursor: raw.Cursor}
+	if page.Cursor != nil && *page.Cursor == "" {
+		page.Cursor = nil
+	}
+	for i, rp := range raw.Posts {
+		p, err := d.post(raw.Feed, rp)
+		if err != nil {
+			return nil, err
+		}
+		page.Posts[i] = p
+	}
+	return page, nil
+}
+
+// post converts a decoded post of feed.
+func (d *PostsDecoder) post(feed string, rp rawPost) (Post, error) {
+	reason, err := decodeReason(rp.Reason)
+	if err != nil {
+		return Post{}, fmt.Errorf("decode getPosts response: post %s: %w", rp.Uri, err)
+	}
+	languages, agree := normalizeLanguages(rp.Languages, rp.Langs)
+	if !agree && d.OnLanguageMismatch != nil {
+		d.OnLanguageMismatch(LanguageMismatch{Feed: feed, Uri: rp.Uri, Languages: rp.Languages, Langs: rp.Langs})
+	}
+	return Post{
+		Uri:         rp.Uri,
+		Cid:         rp.Cid,
+		IndexedAt:   rp.IndexedAt,
+		Languages:   languages,
+		FeedContext: rp.FeedContext,
+		Reason:      reason,
+	}, nil
+}
+
+// rawGetPostsClient is implemented by ClientWithResponses through its
+// embedded ClientInterface. It lets GetPosts read the body itself instead of
+// failing in the generated parser on payloads from older workers.
+type rawGetPostsClient interface {
+	GetGetPosts(ctx context.Context, params *GetGetPostsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+// clientUnwrapper is implemented by wrappers such as SafeClient and
+// Coalescer whose reads go straight to the client they wrap.
+type clientUnwrapper interface {
+	Unwrap() ClientWithResponsesInterface
+}
+
+// rawGetPosts returns the raw GetGetPosts method of c or of the first client
+// it wraps that has one.
+func rawGetPosts(c ClientWithResponsesInterface) (rawGetPostsClient, bool) {
+	for {
+		if rc, ok := c.(rawGetPostsClient); ok {
+			return rc, true
+		}
+		u, ok := c.(clientUnwrapper)
+		if !ok {
+			return nil, false
+		}
+		c = u.Unwrap()
+	}
+}
+
+// GetPosts fetches and decodes one page of posts. Non-200 responses are
+// returned as *APIError.
+func (d *PostsDecoder) GetPosts(ctx context.Context, c ClientWithResponsesInterface, params GetGetPostsParams) (*PostsPage, error) {
+	rc, ok := rawGetPosts(c)
+	if !ok {
+		resp, err := c.GetGetPostsWithResponse(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, newAPIError(OpGetGetPosts, resp.HTTPResponse, resp.Body)
+		}
+		return d.Decode(resp.Body)
+	}
+	rsp, err := rc.GetGetPosts(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, newAPIError(OpGetGetPosts, rsp, body)
+	}
+	return d.Decode(body)
+}
+
+// normalizeLanguages merges languages and the deprecated langs. It reports
+// false when both are set and hold different languages.
+func normalizeLanguages(languages, langs []string) ([]string, bool) {
+	switch {
+	case len(languages) == 0 && len(langs) == 0:
+		return []string{}, true
+	case len(languages) == 0:
+		return langs, true
+	case len(langs) == 0:
+		return languages, true
+	}
+	a, b := slices.Clone(languages), slices.Clone(langs)
+	slices.Sort(a)
+	slices.Sort(b)
+	return languages, slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// decodeReason accepts the reason encodings used by getPosts over time:
+//
+//	{"repost": "at://..."}
+//	{"$type": "app.bsky.feed.defs#skeletonReasonRepost", "repost": "at://..."}
+//	{"$type": "app.bsky.feed.defs#skeletonReasonPin"}
+//	"at://..."
+func decodeReason(raw json.RawMessage) (*Reason, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var uri string
+		if err := json.Unmarshal(raw, &uri); err != nil {
+			return nil, fmt.Errorf("reason: %w", err)
+		}
+		if uri == "" {
+			return nil, nil
+		}
+		return &Reason{Type: ReasonTypeRepost, Repost: &uri}, nil
+	}
+	var obj struct {
+		Type   string  `json:"$type"`
+		Repost *string `json:"repost"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("reason: %w", err)
+	}
+	switch {
+	case obj.Type == string(ReasonTypePin):
+		return &Reason{Type: ReasonTypePin}, nil
+	case obj.Repost != nil:
+		return &Reason{Type: ReasonTypeRepost, Repost: obj.Repost}, nil
+	case obj.Type == "":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("reason: unsupported $type %q", obj.Type)
+}
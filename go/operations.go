@@ -0,0 +1,19 @@
+package client
+
+// Operation names, one per generated client method. They are used in errors
+// and by the instrumentation options.
+const (
+	OpPostAddPost            = "postAddPost"
+	OpPostBatchAddPosts      = "postBatchAddPosts"
+	OpPostBatchRemovePosts   = "postBatchRemovePosts"
+	OpGetGetPosts            = "getGetPosts"
+	OpGetListFeeds           = "getListFeeds"
+	OpPostRegisterFeed       = "postRegisterFeed"
+	OpPostRemovePost         = "postRemovePost"
+	OpPostRemovePostByAuthor = "postRemovePostByAuthor"
+	OpPostTrimFeed           = "postTrimFeed"
+	OpPostUnregisterFeed     = "postUnregisterFeed"
+	OpPostUpdateFeed         = "postUpdateFeed"
+	OpGetPing                = "getPing"
+	OpPostUpdateDocument     = "postUpdateDocument"
+)
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// maxPostsPageSize is the largest limit accepted by getPosts.
+	maxPostsPageSize = 3000
+	// defaultBatchSize is the number of posts sent per batch request.
+	defaultBatchSize = 100
+)
+
+// walkPostPages calls fn for every page of posts in feed, following cursors
+// until the feed is exhausted or fn returns an error.
+func walkPostPages(ctx context.Context, c ClientWithResponsesInterface, feed string, fn func(page *PostsPage) error) error {
+	return walkPostPagesFrom(ctx, c, feed, nil, fn)
+}
+
+// walkPostPagesFrom is like walkPostPages but starts at cursor. A nil cursor
+// starts at the newest post.
+func walkPostPagesFrom(ctx context.Context, c ClientWithResponsesInterface, feed string, cursor *string, fn func(page *PostsPage) error) error {
+	limit := maxPostsPageSize
+	params := GetGetPostsParams{Feed: feed, Limit: &limit, Cursor: cursor}
+	for {
+		page, err := GetPosts(ctx, c, params)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if page.Cursor == nil || len(page.Posts) == 0 {
+			return nil
+		}
+		params.Cursor = page.Cursor
+	}
+}
+
+// collectPosts reads every post of feed.
+func collectPosts(ctx context.Context, c ClientWithResponsesInterface, feed string) ([]Post, error) {
+	var posts []Post
+	for p, err := range AllPosts(ctx, c, feed) {
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, nil
+}
+
+// postAuthor returns the DID of the repository a post URI belongs to.
+func postAuthor(uri string) string {
+	rest, ok := strings.CutPrefix(uri, "at://")
+	if !ok {
+		return ""
+	}
+	author, _, _ := strings.Cut(rest, "/")
+	return author
+}
+
+// countOK returns the number of items that succeeded and the items that failed.
+func countOK(items []BatchItemResult) (int, []BatchItemResult) {
+	ok := 0
+	var failed []BatchItemResult
+	for _, item := range items {
+		if item.OK() {
+			ok++
+		} else {
+			failed = append(failed, item)
+		}
+	}
+	return ok, failed
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FeedManifest is the desired registration state of a set of feeds.
+//
+//	feeds:
+//	  - uri: at://did:plc:1234abcd/app.bsky.feed.generator/record123
+//	    isActive: true
+//	    langFilter: false
+type FeedManifest struct {
+	Feeds []ManifestFeed `yaml:"feeds"`
+}
+
+// ManifestFeed is one feed of a FeedManifest. Settings left unset are not
+// managed: registration uses the server default and existing values are
+// never updated.
+type ManifestFeed struct {
+	Uri        string `yaml:"uri"`
+	IsActive   *bool  `yaml:"isActive,omitempty"`
+	LangFilter *bool  `yaml:"langFilter,omitempty"`
+}
+
+// LoadFeedManifest reads and validates a YAML feed manifest.
+func LoadFeedManifest(path string) (*FeedManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m FeedManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse feed manifest %s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid feed manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks for missing and duplicate feed URIs.
+func (m *FeedManifest) Validate() error {
+	seen := make(map[string]bool, len(m.Feeds))
+	for i, f := range m.Feeds {
+		switch {
+		case f.Uri == "":
+			return fmt.Errorf("feed %d: uri is required", i)
+		case !strings.HasPrefix(f.Uri, "at://"):
+			return fmt.Errorf("feed %d: uri %q is not an at:// URI", i, f.Uri)
+		case seen[f.Uri]:
+			return fmt.Errorf("feed %d: duplicate uri %s", i, f.Uri)
+		}
+		seen[f.Uri] = true
+	}
+	return nil
+}
+
+// PlanActionKind is the change a PlanAction makes.
+type PlanActionKind string
+
+const (
+	ActionRegister   PlanActionKind = "register"
+	ActionUpdate     PlanActionKind = "update"
+	ActionUnregister PlanActionKind = "unregister"
+)
+
+// PlanAction is one change needed to reach the manifest state.
+type PlanAction struct {
+	Kind PlanActionKind `json:"kind"`
+	Uri  string         `json:"uri"`
+	// Current is the registered state, nil for ActionRegister.
+	Current *ManifestFeed `json:"current,omitempty"`
+	// Desired is the manifest state, nil for ActionUnregister.
+	Desired *ManifestFeed `json:"desired,omitempty"`
+}
+
+// Destructive reports whether the action needs explicit approval.
+func (a PlanAction) Destructive() bool {
+	return a.Kind == ActionUnregister
+}
+
+func (a PlanAction) String() string {
+	switch a.Kind {
+	case ActionRegister:
+		return fmt.Sprintf("+ register %s%s", a.Uri, formatSettings(a.Desired))
+	case ActionUpdate:
+		var changes []string
+		if a.Desired.IsActive != nil && *a.Desired.IsActive != *a.Current.IsActive {
+			changes = append(changes, fmt.Sprintf("isActive: %t -> %t", *a.Current.IsActive, *a.Desired.IsActive))
+		}
+		if a.Desired.LangFilter != nil && *a.Desired.LangFilter != *a.Current.LangFilter {
+			changes = append(changes, fmt.Sprintf("langFilter: %t -> %t", *a.Current.LangFilter, *a.Desired.LangFilter))
+		}
+		return fmt.Sprintf("~ update %s (%s)", a.Uri, strings.Join(changes, ", "))
+	case ActionUnregister:
+		return fmt.Sprintf("- unregister %s", a.Uri)
+	}
+	return string(a.Kind) + " " + a.Uri
+}
+
+func formatSettings(f *ManifestFeed) string {
+	var s []string
+	if f.IsActive != nil {
+		s = append(s, fmt.Sprintf("isActive=%t", *f.IsActive))
+	}
+	if f.LangFilter != nil {
+		s = append(s, fmt.Sprintf("langFilter=%t", *f.LangFilter))
+	}
+	if len(s) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(s, ", ") + ")"
+}
+
+// PlanOptions configures FeedManifest.Plan.
+type PlanOptions struct {
+	// Prune plans unregistering feeds that are registered but missing from
+	// the manifest. Without it they are only listed in Plan.Unmanaged.
+	Prune bool
+}
+
+// Plan is the set of actions that brings the registered feeds in line with
+// a manifest.
+type Plan struct {
+	Actions []PlanAction `json:"actions"`
+	// Unmanaged lists registered feeds missing from the manifest that are
+	// left alone because pruning was not requested.
+	Unmanaged []string `json:"unmanaged,omitempty"`
+}
+
+// Empty reports whether there is nothing to do.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes"
+	}
+	var b strings.Builder
+	for _, a := range p.Actions {
+		b.WriteString(a.String())
+		b.WriteByte('\n')
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Plan compares the manifest with GetListFeeds and returns the actions needed
+// to reach the manifest state. Actions are ordered register, update,
+// unregister, each in manifest or server order.
+func (m *FeedManifest) Plan(ctx context.Context, c ClientWithResponsesInterface, opts PlanOptions) (*Plan, error) {
+	registered, err := ListFeeds(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*ManifestFeed, len(registered))
+	for _, f := range registered {
+		current[f.Uri] = &ManifestFeed{Uri: f.Uri, IsActive: &f.IsActive, LangFilter: &f.LangFilter}
+	}
+
+	plan := &Plan{}
+	var updates []PlanAction
+	inManifest := make(map[string]bool, len(m.Feeds))
+	for _, f := range m.Feeds {
+		desired := f
+		inManifest[f.Uri] = true
+		cur, ok := current[f.Uri]
+		if !ok {
+			plan.Actions = append(plan.Actions, PlanAction{Kind: ActionRegister, Uri: f.Uri, Desired: &desired})
+			continue
+		}
+		if (f.IsActive != nil && *f.IsActive != *cur.IsActive) || (f.LangFilter != nil && *f.LangFilter != *cur.LangFilter) {
+			updates = append(updates, PlanAction{Kind: ActionUpdate, Uri: f.Uri, Current: cur, Desired: &desired})
+		}
+	}
+	plan.Actions = append(plan.Actions, updates...)
+	for _, f := range registered {
+		if inManifest[f.Uri] {
+			continue
+		}
+		if opts.Prune {
+			plan.Actions = append(plan.Actions, PlanAction{Kind: ActionUnregister, Uri: f.Uri, Current: current[f.Uri]})
+		} else {
+			plan.Unmanaged = append(plan.Unmanaged, f.Uri)
+		}
+	}
+	return plan, nil
+}
+
+// ErrNotApproved is recorded for destructive actions that were not approved.
+var ErrNotApproved = errors.New("destructive action not approved")
+
+// ApplyOptions configures Plan.Apply.
+type ApplyOptions struct {
+	// Approve is asked before every destructive action. Destructive actions
+	// are skipped when it is nil or returns false.
+	Approve func(PlanAction) bool
+}
+
+// ActionResult is the outcome of applying one PlanAction.
+type ActionResult struct {
+	Action  PlanAction `json:"action"`
+	Applied bool       `json:"applied"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// ApplyResult is the outcome of Plan.Apply.
+type ApplyResult struct {
+	Results []ActionResult `json:"results"`
+}
+
+// Failed returns the number of actions that were not applied.
+func (r *ApplyResult) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if !res.Applied {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply executes the plan with PostRegisterFeed, PostUpdateFeed and
+// PostUnregisterFeed. A failing action does not stop the others. The
+// returned error is only set when ctx is cancelled.
+func (p *Plan) Apply(ctx context.Context, c ClientWithResponsesInterface, opts ApplyOptions) (*ApplyResult, error) {
+	result := &ApplyResult{}
+	for _, a := range p.Actions {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		res := ActionResult{Action: a}
+		err := applyAction(ctx, c, a, opts)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Applied = true
+		}
+		result.Results = append(result.Results, res)
+	}
+	return result, nil
+}
+
+func applyAction(ctx context.Context, c ClientWithResponsesInterface, a PlanAction, opts ApplyOptions) error {
+	if a.Destructive() && (opts.Approve == nil || !opts.Approve(a)) {
+		return ErrNotApproved
+	}
+	switch a.Kind {
+	case ActionRegister:
+		resp, err := c.PostRegisterFeedWithResponse(ctx, PostRegisterFeedJSONRequestBody{Uri: a.Uri, IsActive: a.Desired.IsActive, LangFilter: a.Desired.LangFilter})
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return newAPIError(OpPostRegisterFeed, resp.HTTPResponse, resp.Body)
+		}
+	case ActionUpdate:
+		resp, err := c.PostUpdateFeedWithResponse(ctx, PostUpdateFeedJSONRequestBody{Uri: a.Uri, IsActive: a.Desired.IsActive, LangFilter: a.Desired.LangFilter})
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return newAPIError(OpPostUpdateFeed, resp.HTTPResponse, resp.Body)
+		}
+	case ActionUnregister:
+		resp, err := c.PostUnregisterFeedWithResponse(ctx, PostUnregisterFeedJSONRequestBody{Uri: a.Uri})
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return newAPIError(OpPostUnregisterFeed, resp.HTTPResponse, resp.Body)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", a.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RetentionPolicy bounds the contents of a single feed. Unset limits are not applied.
+type RetentionPolicy struct {
+	Feed string `yaml:"feed"`
+	// MaxPosts keeps only the newest MaxPosts posts, using trimPosts.
+	MaxPosts *int `yaml:"maxPosts,omitempty"`
+	// MaxAge removes posts whose indexedAt is older than MaxAge.
+	MaxAge time.Duration `yaml:"maxAge,omitempty"`
+	// MaxPostsPerAuthor keeps only the newest MaxPostsPerAuthor posts of each author.
+	MaxPostsPerAuthor *int `yaml:"maxPostsPerAuthor,omitempty"`
+}
+
+// needsScan reports whether applying the policy requires reading the feed's posts.
+func (p RetentionPolicy) needsScan() bool {
+	return p.MaxAge > 0 || p.MaxPostsPerAuthor != nil
+}
+
+// RetentionConfig is the file format read by LoadRetentionConfig.
+//
+//	interval: 1h
+//	policies:
+//	  - feed: at://did:plc:1234abcd/app.bsky.feed.generator/record123
+//	    maxPosts: 5000
+//	    maxAge: 168h
+//	    maxPostsPerAuthor: 20
+type RetentionConfig struct {
+	// Interval between scheduled runs.
+	Interval time.Duration `yaml:"interval"`
+	// BatchSize is the number of posts per batchRemovePosts request.
+	BatchSize int               `yaml:"batchSize,omitempty"`
+	Policies  []RetentionPolicy `yaml:"policies"`
+}
+
+// LoadRetentionConfig reads and validates a YAML retention config.
+func LoadRetentionConfig(path string) (*RetentionConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg RetentionConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse retention config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retention config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the config for missing feeds, duplicates and negative limits.
+func (cfg *RetentionConfig) Validate() error {
+	if cfg.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if cfg.BatchSize < 0 {
+		return errors.New("batchSize must not be negative")
+	}
+	seen := make(map[string]bool, len(cfg.Policies))
+	for i, p := range cfg.Policies {
+		switch {
+		case p.Feed == "":
+			return fmt.Errorf("policy %d: feed is required", i)
+		case seen[p.Feed]:
+			return fmt.Errorf("policy %d: duplicate feed %s", i, p.Feed)
+		case p.MaxPosts != nil && *p.MaxPosts < 0:
+			return fmt.Errorf("policy %d: maxPosts must not be negative", i)
+		case p.MaxAge < 0:
+			return fmt.Errorf("policy %d: maxAge must not be negative", i)
+		case p.MaxPostsPerAuthor != nil && *p.MaxPostsPerAuthor < 0:
+			return fmt.Errorf("policy %d: maxPostsPerAuthor must not be negative", i)
+		}
+		seen[p.Feed] = true
+	}
+	return nil
+}
+
+// RetentionResult holds the deleted counts for one feed.
+type RetentionResult struct {
+	Feed string `json:"feed"`
+	// Trimmed is the deletedCount reported by trimPosts.
+	Trimmed int `json:"trimmed"`
+	// Expired is the number of posts removed for exceeding MaxAge.
+	Expired int `json:"expired"`
+	// AuthorCapped is the number of posts removed for exceeding MaxPostsPerAuthor.
+	AuthorCapped int `json:"authorCapped"`
+	// Failures lists posts the server could not remove.
+	Failures []BatchItemResult `json:"failures,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Deleted returns the total number of posts removed from the feed.
+func (r RetentionResult) Deleted() int {
+	return r.Trimmed + r.Expired + r.AuthorCapped
+}
+
+// RetentionReport summarises one run over all policies.
+type RetentionReport struct {
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	Results    []RetentionResult `json:"results"`
+}
+
+// Deleted returns the total number of posts removed in the run.
+func (r *RetentionReport) Deleted() int {
+	n := 0
+	for _, res := range r.Results {
+		n += res.Deleted()
+	}
+	return n
+}
+
+// Failed returns the number of feeds whose policy could not be fully applied.
+func (r *RetentionReport) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// ApplyRetention applies policy to its feed once. Posts older than now-MaxAge
+// and posts beyond the per-author cap are removed first, then the feed is
+// trimmed to MaxPosts. The result holds the counts of whatever succeeded
+// before an error.
+func ApplyRetention(ctx context.Context, c ClientWithResponsesInterface, policy RetentionPolicy, now time.Time, batchSize int) (RetentionResult, error) {
+	return applyRetention(ctx, c, policy, now, batcherOr(nil, batchSize))
+}
+
+func applyRetention(ctx context.Context, c ClientWithResponsesInterface, policy RetentionPolicy, now time.Time, batcher *AdaptiveBatcher) (RetentionResult, error) {
+	res := RetentionResult{Feed: policy.Feed}
+	if policy.needsScan() {
+		expired, capped, err := selectRetentionRemovals(ctx, c, policy, now)
+		if err != nil {
+			return res, err
+		}
+		items, err := batcher.RemovePosts(ctx, c, policy.Feed, expired)
+		res.Expired, res.Failures = countOK(items)
+		if err != nil {
+			return res, err
+		}
+		items, err = batcher.RemovePosts(ctx, c, policy.Feed, capped)
+		removed, failed := countOK(items)
+		res.AuthorCapped, res.Failures = removed, append(res.Failures, failed...)
+		if err != nil {
+			return res, err
+		}
+	}
+	if policy.MaxPosts != nil {
+		resp, err := c.PostTrimFeedWithResponse(ctx, PostTrimFeedJSONRequestBody{Feed: policy.Feed, Remain: *policy.MaxPosts})
+		if err != nil {
+			return res, err
+		}
+		if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+			return res, newAPIError(OpPostTrimFeed, resp.HTTPResponse, resp.Body)
+		}
+		res.Trimmed = int(resp.JSON200.DeletedCount)
+	}
+	return res, nil
+}
+
+// selectRetentionRemovals scans the feed and returns the posts to remove for
+// being too old and for exceeding the per-author cap. A post is only
+// returned in one of the two lists.
+func selectRetentionRemovals(ctx context.Context, c ClientWithResponsesInterface, policy RetentionPolicy, now time.Time) (expired, capped []Post, err error) {
+	var cutoff time.Time
+	if policy.MaxAge > 0 {
+		cutoff = now.Add(-policy.MaxAge)
+	}
+	byAuthor := make(map[string][]Post)
+	err = walkPostPages(ctx, c, policy.Feed, func(page *PostsPage) error {
+		for _, p := range page.Posts {
+			if !cutoff.IsZero() && p.IndexedAt.Before(cutoff) {
+				expired = append(expired, p)
+				continue
+			}
+			if policy.MaxPostsPerAuthor != nil {
+				byAuthor[p.Author()] = append(byAuthor[p.Author()], p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy.MaxPostsPerAuthor == nil {
+		return expired, nil, nil
+	}
+	limit := *policy.MaxPostsPerAuthor
+	authors := make([]string, 0, len(byAuthor))
+	for author := range byAuthor {
+		authors = append(authors, author)
+	}
+	sort.Strings(authors)
+	for _, author := range authors {
+		posts := byAuthor[author]
+		if len(posts) <= limit {
+			continue
+		}
+		sort.SliceStable(posts, func(i, j int) bool { return posts[i].IndexedAt.After(posts[j].IndexedAt) })
+		capped = append(capped, posts[limit:]...)
+	}
+	return expired, capped, nil
+}
+
+// RetentionScheduler applies a RetentionConfig periodically.
+type RetentionScheduler struct {
+	Client ClientWithResponsesInterface
+	Config *RetentionConfig
+	// OnReport, if set, is called after every run.
+	OnReport func(*RetentionReport)
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// Batcher, if set, sizes the batchRemovePosts requests instead of
+	// Config.BatchSize.
+	Batcher *AdaptiveBatcher
+}
+
+// NewRetentionScheduler creates a scheduler for cfg.
+func NewRetentionScheduler(c ClientWithResponsesInterface, cfg *RetentionConfig) *RetentionScheduler {
+	return &RetentionScheduler{Client: c, Config: cfg}
+}
+
+func (s *RetentionScheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// RunOnce applies every policy once. A failing policy does not stop the
+// others; its error is recorded in the report.
+func (s *RetentionScheduler) RunOnce(ctx context.Context) *RetentionReport {
+	report := &RetentionReport{StartedAt: s.now()}
+	batcher := batcherOr(s.Batcher, s.Config.BatchSize)
+	for _, policy := range s.Config.Policies {
+		if ctx.Err() != nil {
+			report.Results = append(report.Results, RetentionResult{Feed: policy.Feed, Error: ctx.Err().Error()})
+			continue
+		}
+		res, err := applyRetention(ctx, s.Client, policy, report.StartedAt, batcher)
+		if err != nil {
+			res.Error = err.Error()
+		}
+		report.Results = append(report.Results, res)
+	}
+	report.FinishedAt = s.now()
+	if s.OnReport != nil {
+		s.OnReport(report)
+	}
+	return report
+}
+
+// Run applies the policies immediately and then every Config.Interval until
+// ctx is done. It returns ctx.Err().
+func (s *RetentionScheduler) Run(ctx context.Context) error {
+	if s.Config.Interval <= 0 {
+		return errors.New("retention: interval must be positive")
+	}
+	ticker := time.NewTicker(s.Config.Interval)
+	defer ticker.Stop()
+	for {
+		s.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
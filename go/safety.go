@@ -0,0 +1,294 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// SafetyRule identifies the guardrail that denied a call.
+type SafetyRule string
+
+const (
+	// SafetyMinRemain: a trim would leave fewer than MinRemain posts.
+	SafetyMinRemain SafetyRule = "minRemain"
+	// SafetyTrimFraction: a trim would remove more than MaxTrimFraction of
+	// the feed's posts.
+	SafetyTrimFraction SafetyRule = "trimFraction"
+	// SafetyAuthorRemovals: removePostByAuthor would delete more than
+	// MaxAuthorRemovals posts.
+	SafetyAuthorRemovals SafetyRule = "authorRemovals"
+	// SafetyUnregister: every unregisterFeed is guarded.
+	SafetyUnregister SafetyRule = "unregister"
+)
+
+// SafetyPolicy sets the guardrails of a SafeClient. Zero fields disable
+// their check; unregisterFeed is always guarded.
+type SafetyPolicy struct {
+	// MinRemain denies trims with a remain below it.
+	MinRemain int
+	// MaxTrimFraction denies trims that would remove more than this fraction
+	// of the feed's posts, e.g. 0.5. The posts are counted with getPosts
+	// first.
+	MaxTrimFraction float64
+	// MaxAuthorRemovals denies removePostByAuthor calls that would delete
+	// more than this many posts. The posts are counted with getPosts first.
+	MaxAuthorRemovals int
+	// Block denies guarded calls outright. Otherwise a denied call can be
+	// retried with the token of its SafetyError set by WithConfirmation.
+	Block bool
+}
+
+// DefaultSafetyPolicy is a starting point for production instances.
+var DefaultSafetyPolicy = SafetyPolicy{
+	MinRemain:         100,
+	MaxTrimFraction:   0.5,
+	MaxAuthorRemovals: 100,
+}
+
+// SafetyError is returned by a SafeClient for a call its policy denied.
+// Nothing was sent to the server.
+type SafetyError struct {
+	Operation string
+	Feed      string
+	Rule      SafetyRule
+	Reason    string
+	// Token confirms exactly this call with WithConfirmation. It is empty
+	// when the policy blocks guarded calls.
+	Token string
+}
+
+func (e *SafetyError) Error() string {
+	msg := fmt.Sprintf("gyoka: %s %s denied by safety policy: %s", e.Operation, e.Feed, e.Reason)
+	if e.Token != "" {
+		msg += fmt.Sprintf(" (confirm with token %s)", e.Token)
+	}
+	return msg
+}
+
+type confirmationKey struct{}
+
+// WithConfirmation returns a context that lets the calls confirmed by
+// tokens through a SafeClient. Tokens come from SafetyError.Token and only
+// match the call with the same operation, feed and parameters.
+func WithConfirmation(ctx context.Context, tokens ...string) context.Context {
+	prev, _ := ctx.Value(confirmationKey{}).([]string)
+	return context.WithValue(ctx, confirmationKey{}, append(slices.Clip(prev), tokens...))
+}
+
+func confirmed(ctx context.Context, token string) bool {
+	tokens, _ := ctx.Value(confirmationKey{}).([]string)
+	return slices.Contains(tokens, token)
+}
+
+// confirmationToken derives the token of a call from its operation, feed
+// and guarded parameter.
+func confirmationToken(op, feed, param string) string {
+	sum := sha256.Sum256([]byte(op + "\x00" + feed + "\x00" + param))
+	return hex.EncodeToString(sum[:6])
+}
+
+// SafeClient is a ClientWithResponsesInterface that checks trimFeed,
+// removePostByAuthor and unregisterFeed calls against a SafetyPolicy
+// before sending them. Denied calls return a *SafetyError. The *WithBody
+// variants are decoded and checked the same way. All other methods go
+// straight to the wrapped client.
+//
+// With a Journal, the posts a removePostByAuthor or trimFeed call is about
+// to remove are fetched with getPosts and saved to it before the call is
+// sent, so Undo can add them back. Entries of calls answered with a non-200
+// status are deleted again.
+type SafeClient struct {
+	ClientWithResponsesInterface
+	Policy  SafetyPolicy
+	Journal *UndoJournal
+}
+
+// NewSafeClient wraps c.
+func NewSafeClient(c ClientWithResponsesInterface, policy SafetyPolicy) *SafeClient {
+	return &SafeClient{ClientWithResponsesInterface: c, Policy: policy}
+}
+
+// Unwrap returns the wrapped client.
+func (s *SafeClient) Unwrap() ClientWithResponsesInterface {
+	return s.ClientWithResponsesInterface
+}
+
+// Undo restores the posts of the journal entry with id. See
+// UndoJournal.Undo.
+func (s *SafeClient) Undo(ctx context.Context, id string) (*UndoResult, error) {
+	if s.Journal == nil {
+		return nil, errors.New("gyoka: SafeClient has no undo journal")
+	}
+	return s.Journal.Undo(ctx, s.ClientWithResponsesInterface, id)
+}
+
+// deny returns the SafetyError for a call, or nil if ctx confirms it.
+func (s *SafeClient) deny(ctx context.Context, op, feed, param string, rule SafetyRule, format string, args ...any) error {
+	e := &SafetyError{Operation: op, Feed: feed, Rule: rule, Reason: fmt.Sprintf(format, args...)}
+	if !s.Policy.Block {
+		e.Token = confirmationToken(op, feed, param)
+		if confirmed(ctx, e.Token) {
+			return nil
+		}
+	}
+	return e
+}
+
+// journal saves e when s has a Journal and returns its ID, or "".
+func (s *SafeClient) journal(e *JournalEntry) (string, error) {
+	if s.Journal == nil {
+		return "", nil
+	}
+	if err := s.Journal.record(e); err != nil {
+		return "", fmt.Errorf("gyoka: undo journal: %w", err)
+	}
+	return e.ID, nil
+}
+
+// settle deletes the journal entry id of a call answered with status,
+// unless the call succeeded. Entries of calls without a response are kept,
+// as the server may have applied them.
+func (s *SafeClient) settle(id string, status int) {
+	if id != "" && status != http.StatusOK {
+		_ = s.Journal.discard(id)
+	}
+}
+
+// guardTrim checks a trimFeed call and journals the posts it would remove.
+// It returns the journal entry ID, or "" without a Journal.
+func (s *SafeClient) guardTrim(ctx context.Context, body PostTrimFeedJSONRequestBody) (string, error) {
+	param := strconv.Itoa(body.Remain)
+	if body.Remain < s.Policy.MinRemain {
+		if err := s.deny(ctx, OpPostTrimFeed, body.Feed, param, SafetyMinRemain, "remain %d is below %d", body.Remain, s.Policy.MinRemain); err != nil {
+			return "", err
+		}
+	}
+	if s.Policy.MaxTrimFraction <= 0 && s.Journal == nil {
+		return "", nil
+	}
+	posts, err := collectPosts(ctx, s.ClientWithResponsesInterface, body.Feed)
+	if err != nil {
+		return "", fmt.Errorf("gyoka: safety check for %s: %w", OpPostTrimFeed, err)
+	}
+	// Posts are listed newest first, and trimming keeps the newest.
+	removed := posts[min(max(body.Remain, 0), len(posts)):]
+	if s.Policy.MaxTrimFraction > 0 && len(posts) > 0 && float64(len(removed))/float64(len(posts)) > s.Policy.MaxTrimFraction {
+		if err := s.deny(ctx, OpPostTrimFeed, body.Feed, param, SafetyTrimFraction, "trim would remove %d of %d posts", len(removed), len(posts)); err != nil {
+			return "", err
+		}
+	}
+	return s.journal(&JournalEntry{Operation: OpPostTrimFeed, Feed: body.Feed, Remain: &body.Remain, Posts: removed})
+}
+
+// guardRemoveByAuthor checks a removePostByAuthor call and journals the
+// posts it would remove.
+func (s *SafeClient) guardRemoveByAuthor(ctx context.Context, body PostRemovePostByAuthorJSONRequestBody) (string, error) {
+	if s.Policy.MaxAuthorRemovals <= 0 && s.Journal == nil {
+		return "", nil
+	}
+	posts, err := collectPosts(ctx, s.ClientWithResponsesInterface, body.Feed)
+	if err != nil {
+		return "", fmt.Errorf("gyoka: safety check for %s: %w", OpPostRemovePostByAuthor, err)
+	}
+	removed := slices.DeleteFunc(posts, func(p Post) bool { return p.Author() != body.Author })
+	if s.Policy.MaxAuthorRemovals > 0 && len(removed) > s.Policy.MaxAuthorRemovals {
+		if err := s.deny(ctx, OpPostRemovePostByAuthor, body.Feed, body.Author, SafetyAuthorRemovals, "would remove %d posts of %s", len(removed), body.Author); err != nil {
+			return "", err
+		}
+	}
+	return s.journal(&JournalEntry{Operation: OpPostRemovePostByAuthor, Feed: body.Feed, Author: body.Author, Posts: removed})
+}
+
+func (s *SafeClient) guardUnregister(ctx context.Context, body PostUnregisterFeedJSONRequestBody) (string, error) {
+	return "", s.deny(ctx, OpPostUnregisterFeed, body.Uri, "", SafetyUnregister, "unregistering a feed deletes all of its posts")
+}
+
+func (s *SafeClient) PostTrimFeedWithResponse(ctx context.Context, body PostTrimFeedJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTrimFeedResponse, error) {
+	id, err := s.guardTrim(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ClientWithResponsesInterface.PostTrimFeedWithResponse(ctx, body, reqEditors...)
+	if err == nil {
+		s.settle(id, resp.StatusCode())
+	}
+	return resp, err
+}
+
+func (s *SafeClient) PostTrimFeedWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTrimFeedResponse, error) {
+	data, id, err := decodeGuardedBody(ctx, body, s.guardTrim)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ClientWithResponsesInterface.PostTrimFeedWithBodyWithResponse(ctx, contentType, bytes.NewReader(data), reqEditors...)
+	if err == nil {
+		s.settle(id, resp.StatusCode())
+	}
+	return resp, err
+}
+
+func (s *SafeClient) PostRemovePostByAuthorWithResponse(ctx context.Context, body PostRemovePostByAuthorJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRemovePostByAuthorResponse, error) {
+	id, err := s.guardRemoveByAuthor(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ClientWithResponsesInterface.PostRemovePostByAuthorWithResponse(ctx, body, reqEditors...)
+	if err == nil {
+		s.settle(id, resp.StatusCode())
+	}
+	return resp, err
+}
+
+func (s *SafeClient) PostRemovePostByAuthorWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRemovePostByAuthorResponse, error) {
+	data, id, err := decodeGuardedBody(ctx, body, s.guardRemoveByAuthor)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ClientWithResponsesInterface.PostRemovePostByAuthorWithBodyWithResponse(ctx, contentType, bytes.NewReader(data), reqEditors...)
+	if err == nil {
+		s.settle(id, resp.StatusCode())
+	}
+	return resp, err
+}
+
+func (s *SafeClient) PostUnregisterFeedWithResponse(ctx context.Context, body PostUnregisterFeedJSONRequestBody, reqEditors ...RequestEditorFn) (*PostUnregisterFeedResponse, error) {
+	if _, err := s.guardUnregister(ctx, body); err != nil {
+		return nil, err
+	}
+	return s.ClientWithResponsesInterface.PostUnregisterFeedWithResponse(ctx, body, reqEditors...)
+}
+
+func (s *SafeClient) PostUnregisterFeedWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostUnregisterFeedResponse, error) {
+	data, _, err := decodeGuardedBody(ctx, body, s.guardUnregister)
+	if err != nil {
+		return nil, err
+	}
+	return s.ClientWithResponsesInterface.PostUnregisterFeedWithBodyWithResponse(ctx, contentType, bytes.NewReader(data), reqEditors...)
+}
+
+// decodeGuardedBody reads a raw request body, runs guard on it and returns
+// the bytes to send and the journal entry ID.
+func decodeGuardedBody[B any](ctx context.Context, body io.Reader, guard func(context.Context, B) (string, error)) ([]byte, string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+	var b B
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, "", fmt.Errorf("gyoka: safety check: decode request body: %w", err)
+	}
+	id, err := guard(ctx, b)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, id, nil
+}
@@ -0,0 +1,59 @@
+package server
+
+import client "github.com/nus25/gyoka-client/go"
+
+// The strict server refers to the enum types of inline response schemas by
+// names that oapi-codegen only emits, without the JSONResponse infix, when
+// generating the client. They are aliased to the client's types so both sides
+// share one definition.
+type (
+	GetGetPosts400JSONResponseError                         = client.GetGetPosts400Error
+	GetGetPosts401JSONResponseError                         = client.GetGetPosts401Error
+	GetGetPosts404JSONResponseError                         = client.GetGetPosts404Error
+	GetGetPosts500JSONResponseError                         = client.GetGetPosts500Error
+	GetListFeeds401JSONResponseError                        = client.GetListFeeds401Error
+	GetListFeeds500JSONResponseError                        = client.GetListFeeds500Error
+	GetPing401JSONResponseError                             = client.GetPing401Error
+	GetPing500JSONResponseError                             = client.GetPing500Error
+	PostAddPost200JSONResponsePostReasonType                = client.PostAddPost200PostReasonType
+	PostAddPost400JSONResponseError                         = client.PostAddPost400Error
+	PostAddPost401JSONResponseError                         = client.PostAddPost401Error
+	PostAddPost404JSONResponseError                         = client.PostAddPost404Error
+	PostAddPost500JSONResponseError                         = client.PostAddPost500Error
+	PostBatchAddPosts200JSONResponseResultsResultsStatus    = client.PostBatchAddPosts200ResultsResultsStatus
+	PostBatchAddPosts400JSONResponseError                   = client.PostBatchAddPosts400Error
+	PostBatchAddPosts401JSONResponseError                   = client.PostBatchAddPosts401Error
+	PostBatchAddPosts500JSONResponseError                   = client.PostBatchAddPosts500Error
+	PostBatchRemovePosts200JSONResponseResultsResultsStatus = client.PostBatchRemovePosts200ResultsResultsStatus
+	PostBatchRemovePosts400JSONResponseError                = client.PostBatchRemovePosts400Error
+	PostBatchRemovePosts401JSONResponseError                = client.PostBatchRemovePosts401Error
+	PostBatchRemovePosts500JSONResponseError                = client.PostBatchRemovePosts500Error
+	PostRegisterFeed400JSONResponseError                    = client.PostRegisterFeed400Error
+	PostRegisterFeed401JSONResponseError                    = client.PostRegisterFeed401Error
+	PostRegisterFeed409JSONResponseError                    = client.PostRegisterFeed409Error
+	PostRegisterFeed500JSONResponseError                    = client.PostRegisterFeed500Error
+	PostRemovePost400JSONResponseError                      = client.PostRemovePost400Error
+	PostRemovePost401JSONResponseError                      = client.PostRemovePost401Error
+	PostRemovePost404JSONResponseError                      = client.PostRemovePost404Error
+	PostRemovePost500JSONResponseError                      = client.PostRemovePost500Error
+	PostRemovePostByAuthor400JSONResponseError              = client.PostRemovePostByAuthor400Error
+	PostRemovePostByAuthor401JSONResponseError              = client.PostRemovePostByAuthor401Error
+	PostRemovePostByAuthor404JSONResponseError              = client.PostRemovePostByAuthor404Error
+	PostRemovePostByAuthor500JSONResponseError              = client.PostRemovePostByAuthor500Error
+	PostTrimFeed400JSONResponseError                        = client.PostTrimFeed400Error
+	PostTrimFeed401JSONResponseError                        = client.PostTrimFeed401Error
+	PostTrimFeed404JSONResponseError                        = client.PostTrimFeed404Error
+	PostTrimFeed500JSONResponseError                        = client.PostTrimFeed500Error
+	PostUnregisterFeed400JSONResponseError                  = client.PostUnregisterFeed400Error
+	PostUnregisterFeed401JSONResponseError                  = client.PostUnregisterFeed401Error
+	PostUnregisterFeed404JSONResponseError                  = client.PostUnregisterFeed404Error
+	PostUnregisterFeed500JSONResponseError                  = client.PostUnregisterFeed500Error
+	PostUpdateDocument200JSONResponseType                   = client.PostUpdateDocument200Type
+	PostUpdateDocument400JSONResponseError                  = client.PostUpdateDocument400Error
+	PostUpdateDocument401JSONResponseError                  = client.PostUpdateDocument401Error
+	PostUpdateDocument500JSONResponseError                  = client.PostUpdateDocument500Error
+	PostUpdateFeed400JSONResponseError                      = client.PostUpdateFeed400Error
+	PostUpdateFeed401JSONResponseError                      = client.PostUpdateFeed401Error
+	PostUpdateFeed404JSONResponseError                      = client.PostUpdateFeed404Error
+	PostUpdateFeed500JSONResponseError                      = client.PostUpdateFeed500Error
+)
@@ -0,0 +1,458 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGetPostsLimit = 1000
+	maxGetPostsLimit     = 3000
+)
+
+// MemoryServer is a reference StrictServerInterface that keeps feeds and
+// posts in memory. It is meant for tests, fakes and local development.
+type MemoryServer struct {
+	// APIKey, if set, is required in the X-API-Key header by Handler.
+	APIKey string
+	// Now returns the time used for posts without indexedAt. Defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	feeds     map[string]*memoryFeed
+	order     []string
+	documents map[PostUpdateDocumentJSONBodyType]PostUpdateDocumentJSONBody
+}
+
+type memoryFeed struct {
+	isActive   bool
+	langFilter bool
+	posts      map[string]memoryPost
+}
+
+type memoryPost struct {
+	uri         string
+	cid         string
+	indexedAt   time.Time
+	languages   []string
+	feedContext *string
+	reasonType  string
+	repost      *string
+}
+
+var _ StrictServerInterface = (*MemoryServer)(nil)
+
+// NewMemoryServer creates an empty MemoryServer.
+func NewMemoryServer() *MemoryServer {
+	return &MemoryServer{
+		feeds:     make(map[string]*memoryFeed),
+		documents: make(map[PostUpdateDocumentJSONBodyType]PostUpdateDocumentJSONBody),
+	}
+}
+
+// Handler returns the server as an http.Handler routed with the generated
+// std-http glue. Requests without the configured APIKey get 401, and
+// request bodies with a Content-Encoding other than identity get 415.
+func (s *MemoryServer) Handler() http.Handler {
+	h := HandlerWithOptions(NewStrictHandler(s, nil), StdHTTPServerOptions{
+		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		},
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.APIKey != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(s.APIKey)) != 1 {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "Authentication credentials were missing or invalid.")
+			return
+		}
+		if enc := r.Header.Get("Content-Encoding"); enc != "" && !strings.EqualFold(enc, "identity") {
+			writeError(w, http.StatusUnsupportedMediaType, "UnsupportedMediaType", fmt.Sprintf("Content-Encoding %s is not supported.", enc))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code, "message": message})
+}
+
+func (s *MemoryServer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func unknownFeed(uri string) *string {
+	msg := fmt.Sprintf("Feed with URI %s does not exist.", uri)
+	return &msg
+}
+
+func message(msg string) *string {
+	return &msg
+}
+
+// makeSlice returns a non-nil slice of length n of the same type as s, so
+// lists in responses encode as [] rather than null.
+func makeSlice[S ~[]E, E any](_ S, n int) S {
+	return make(S, n)
+}
+
+// alloc returns a new zero value for the anonymous struct behind p, so
+// nested response structs can be filled without spelling out their types.
+func alloc[T any](*T) *T {
+	return new(T)
+}
+
+// addPost stores p in f. It reports an error message for invalid posts.
+func (s *MemoryServer) addPost(f *memoryFeed, uri, cid string, indexedAt *time.Time, languages *[]string, feedContext *string, reasonType string, repost *string) (memoryPost, string) {
+	if !strings.HasPrefix(uri, "at://") {
+		return memoryPost{}, "uri must be an at:// URI"
+	}
+	if len(cid) < 8 || len(cid) > 128 {
+		return memoryPost{}, "cid must be between 8 and 128 characters"
+	}
+	p := memoryPost{uri: uri, cid: cid, indexedAt: s.now().UTC(), languages: []string{}, feedContext: feedContext, reasonType: reasonType, repost: repost}
+	if indexedAt != nil {
+		p.indexedAt = *indexedAt
+	}
+	if languages != nil {
+		p.languages = slices.Clone(*languages)
+	}
+	f.posts[uri] = p
+	return p, ""
+}
+
+// sortedPosts returns the posts of f, newest first.
+func (f *memoryFeed) sortedPosts() []memoryPost {
+	posts := make([]memoryPost, 0, len(f.posts))
+	for _, p := range f.posts {
+		posts = append(posts, p)
+	}
+	slices.SortFunc(posts, comparePosts)
+	return posts
+}
+
+func comparePosts(a, b memoryPost) int {
+	if c := b.indexedAt.Compare(a.indexedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(b.uri, a.uri)
+}
+
+// Cursors are "<indexedAt RFC 3339>::<uri>" of the last post of a page.
+func encodeCursor(p memoryPost) string {
+	return p.indexedAt.Format(time.RFC3339Nano) + "::" + p.uri
+}
+
+func decodeCursor(cursor string) (memoryPost, bool) {
+	ts, uri, ok := strings.Cut(cursor, "::")
+	if !ok {
+		return memoryPost{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return memoryPost{}, false
+	}
+	return memoryPost{uri: uri, indexedAt: t}, true
+}
+
+func (s *MemoryServer) PostAddPost(ctx context.Context, request PostAddPostRequestObject) (PostAddPostResponseObject, error) {
+	if request.Body == nil {
+		return PostAddPost400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[request.Body.Feed]
+	if !ok {
+		return PostAddPost404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(request.Body.Feed)}, nil
+	}
+	in := request.Body.Post
+	var reasonType string
+	var repost *string
+	if in.Reason != nil {
+		reasonType, repost = string(in.Reason.Type), in.Reason.Repost
+	}
+	p, msg := s.addPost(f, in.Uri, in.Cid, in.IndexedAt, in.Languages, in.FeedContext, reasonType, repost)
+	if msg != "" {
+		return PostAddPost400JSONResponse{Error: "BadRequest", Message: message(msg)}, nil
+	}
+	resp := PostAddPost200JSONResponse{Feed: request.Body.Feed, Message: "Post added successfully"}
+	resp.Post.Uri, resp.Post.Cid, resp.Post.IndexedAt = p.uri, p.cid, p.indexedAt
+	resp.Post.Languages, resp.Post.FeedContext = p.languages, p.feedContext
+	if p.reasonType != "" {
+		resp.Post.Reason = alloc(resp.Post.Reason)
+		resp.Post.Reason.Type = PostAddPost200JSONResponsePostReasonType(p.reasonType)
+		resp.Post.Reason.Repost = p.repost
+	}
+	return resp, nil
+}
+
+func (s *MemoryServer) PostBatchAddPosts(ctx context.Context, request PostBatchAddPostsRequestObject) (PostBatchAddPostsResponseObject, error) {
+	if request.Body == nil {
+		return PostBatchAddPosts400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var resp PostBatchAddPosts200JSONResponse
+	resp.Results = makeSlice(resp.Results, len(request.Body.Entries))
+	for i, entry := range request.Body.Entries {
+		out := &resp.Results[i]
+		out.Feed = entry.Feed
+		out.Results = makeSlice(out.Results, len(entry.Posts))
+		f, ok := s.feeds[entry.Feed]
+		for j, in := range entry.Posts {
+			item := &out.Results[j]
+			item.Uri, item.Status = in.Uri, "added"
+			if !ok {
+				item.Status, item.Error = "error", unknownFeed(entry.Feed)
+				continue
+			}
+			var reasonType string
+			var repost *string
+			if in.Reason != nil {
+				reasonType, repost = string(in.Reason.Type), in.Reason.Repost
+			}
+			if _, msg := s.addPost(f, in.Uri, in.Cid, in.IndexedAt, in.Languages, in.FeedContext, reasonType, repost); msg != "" {
+				item.Status, item.Error = "error", &msg
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *MemoryServer) PostBatchRemovePosts(ctx context.Context, request PostBatchRemovePostsRequestObject) (PostBatchRemovePostsResponseObject, error) {
+	if request.Body == nil {
+		return PostBatchRemovePosts400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var resp PostBatchRemovePosts200JSONResponse
+	resp.Results = makeSlice(resp.Results, len(request.Body.Entries))
+	for i, entry := range request.Body.Entries {
+		out := &resp.Results[i]
+		out.Feed = entry.Feed
+		out.Results = makeSlice(out.Results, len(entry.Posts))
+		f, ok := s.feeds[entry.Feed]
+		for j, in := range entry.Posts {
+			item := &out.Results[j]
+			item.Uri, item.Status = in.Uri, "removed"
+			if !ok {
+				item.Status, item.Error = "error", unknownFeed(entry.Feed)
+				continue
+			}
+			if _, found := f.posts[in.Uri]; !found {
+				msg := "post not found"
+				item.Status, item.Error = "error", &msg
+				continue
+			}
+			delete(f.posts, in.Uri)
+		}
+	}
+	return resp, nil
+}
+
+func (s *MemoryServer) GetGetPosts(ctx context.Context, request GetGetPostsRequestObject) (GetGetPostsResponseObject, error) {
+	params := request.Params
+	limit := defaultGetPostsLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit <= 0 || limit > maxGetPostsLimit {
+		return GetGetPosts400JSONResponse{Error: "BadRequest", Message: message(fmt.Sprintf("limit must be between 1 and %d", maxGetPostsLimit))}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[params.Feed]
+	if !ok {
+		return GetGetPosts404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(params.Feed)}, nil
+	}
+	posts := f.sortedPosts()
+	if params.Cursor != nil && *params.Cursor != "" {
+		after, ok := decodeCursor(*params.Cursor)
+		if !ok {
+			return GetGetPosts400JSONResponse{Error: "BadRequest", Message: message("invalid cursor")}, nil
+		}
+		start, _ := slices.BinarySearchFunc(posts, after, comparePosts)
+		if start < len(posts) && comparePosts(posts[start], after) == 0 {
+			start++
+		}
+		posts = posts[start:]
+	}
+	resp := GetGetPosts200JSONResponse{Feed: params.Feed}
+	if len(posts) > limit {
+		posts = posts[:limit]
+		cursor := encodeCursor(posts[limit-1])
+		resp.Cursor = &cursor
+	}
+	resp.Posts = makeSlice(resp.Posts, len(posts))
+	for i, p := range posts {
+		out := &resp.Posts[i]
+		out.Uri, out.Cid, out.IndexedAt = p.uri, p.cid, p.indexedAt
+		out.Languages, out.FeedContext = p.languages, p.feedContext
+		if p.reasonType == "app.bsky.feed.defs#skeletonReasonRepost" && p.repost != nil {
+			out.Reason = alloc(out.Reason)
+			out.Reason.Repost = *p.repost
+		}
+	}
+	return resp, nil
+}
+
+func (s *MemoryServer) GetListFeeds(ctx context.Context, request GetListFeedsRequestObject) (GetListFeedsResponseObject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var resp GetListFeeds200JSONResponse
+	resp.Feeds = makeSlice(resp.Feeds, len(s.order))
+	for i, uri := range s.order {
+		f := s.feeds[uri]
+		resp.Feeds[i].Uri, resp.Feeds[i].IsActive, resp.Feeds[i].LangFilter = uri, f.isActive, f.langFilter
+	}
+	return resp, nil
+}
+
+func (s *MemoryServer) PostRegisterFeed(ctx context.Context, request PostRegisterFeedRequestObject) (PostRegisterFeedResponseObject, error) {
+	body := request.Body
+	if body == nil || !strings.HasPrefix(body.Uri, "at://") {
+		return PostRegisterFeed400JSONResponse{Error: "BadRequest", Message: message("uri must be an at:// URI")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.feeds[body.Uri]; ok {
+		return PostRegisterFeed409JSONResponse{Error: "Conflict", Message: message("The requested resource already exists.")}, nil
+	}
+	f := &memoryFeed{isActive: true, langFilter: true, posts: make(map[string]memoryPost)}
+	if body.IsActive != nil {
+		f.isActive = *body.IsActive
+	}
+	if body.LangFilter != nil {
+		f.langFilter = *body.LangFilter
+	}
+	s.feeds[body.Uri] = f
+	s.order = append(s.order, body.Uri)
+	resp := PostRegisterFeed200JSONResponse{Message: "Feed registered successfully"}
+	resp.Feed.Uri, resp.Feed.IsActive, resp.Feed.LangFilter = body.Uri, f.isActive, f.langFilter
+	return resp, nil
+}
+
+func (s *MemoryServer) PostRemovePost(ctx context.Context, request PostRemovePostRequestObject) (PostRemovePostResponseObject, error) {
+	if request.Body == nil {
+		return PostRemovePost400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[request.Body.Feed]
+	if !ok {
+		return PostRemovePost404JSONResponse{Error: "NotFound", Message: unknownFeed(request.Body.Feed)}, nil
+	}
+	p, ok := f.posts[request.Body.Post.Uri]
+	if !ok {
+		return PostRemovePost404JSONResponse{Error: "NotFound", Message: message("The requested resource was not found")}, nil
+	}
+	delete(f.posts, p.uri)
+	resp := PostRemovePost200JSONResponse{Feed: request.Body.Feed, Message: "Post removed successfully"}
+	resp.Post.Uri, resp.Post.IndexedAt = p.uri, p.indexedAt
+	return resp, nil
+}
+
+func (s *MemoryServer) PostRemovePostByAuthor(ctx context.Context, request PostRemovePostByAuthorRequestObject) (PostRemovePostByAuthorResponseObject, error) {
+	body := request.Body
+	if body == nil || !strings.HasPrefix(body.Author, "did:") {
+		return PostRemovePostByAuthor400JSONResponse{Error: "BadRequest", Message: message("author must be a DID")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[body.Feed]
+	if !ok {
+		return PostRemovePostByAuthor404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(body.Feed)}, nil
+	}
+	prefix := "at://" + body.Author + "/"
+	deleted := 0
+	for uri := range f.posts {
+		if strings.HasPrefix(uri, prefix) {
+			delete(f.posts, uri)
+			deleted++
+		}
+	}
+	return PostRemovePostByAuthor200JSONResponse{Author: body.Author, Feed: body.Feed, DeletedCount: deleted, Message: "Posts removed successfully"}, nil
+}
+
+func (s *MemoryServer) PostTrimFeed(ctx context.Context, request PostTrimFeedRequestObject) (PostTrimFeedResponseObject, error) {
+	body := request.Body
+	if body == nil || body.Remain < 0 {
+		return PostTrimFeed400JSONResponse{Error: "BadRequest", Message: message("remain must not be negative")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[body.Feed]
+	if !ok {
+		return PostTrimFeed404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(body.Feed)}, nil
+	}
+	posts := f.sortedPosts()
+	deleted := 0
+	for _, p := range posts[min(body.Remain, len(posts)):] {
+		delete(f.posts, p.uri)
+		deleted++
+	}
+	return PostTrimFeed200JSONResponse{Feed: body.Feed, DeletedCount: float32(deleted), Message: "Feed trimmed successfully"}, nil
+}
+
+func (s *MemoryServer) PostUnregisterFeed(ctx context.Context, request PostUnregisterFeedRequestObject) (PostUnregisterFeedResponseObject, error) {
+	if request.Body == nil {
+		return PostUnregisterFeed400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	uri := request.Body.Uri
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.feeds[uri]; !ok {
+		return PostUnregisterFeed404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(uri)}, nil
+	}
+	delete(s.feeds, uri)
+	s.order = slices.DeleteFunc(s.order, func(u string) bool { return u == uri })
+	return PostUnregisterFeed200JSONResponse{Message: "Feed unregistered successfully"}, nil
+}
+
+func (s *MemoryServer) PostUpdateFeed(ctx context.Context, request PostUpdateFeedRequestObject) (PostUpdateFeedResponseObject, error) {
+	body := request.Body
+	if body == nil {
+		return PostUpdateFeed400JSONResponse{Error: "BadRequest", Message: message("missing body")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[body.Uri]
+	if !ok {
+		return PostUpdateFeed404JSONResponse{Error: "UnknownFeed", Message: unknownFeed(body.Uri)}, nil
+	}
+	if body.IsActive != nil {
+		f.isActive = *body.IsActive
+	}
+	if body.LangFilter != nil {
+		f.langFilter = *body.LangFilter
+	}
+	resp := PostUpdateFeed200JSONResponse{Message: "Feed updated successfully"}
+	resp.Feed.Uri, resp.Feed.IsActive, resp.Feed.LangFilter = body.Uri, f.isActive, f.langFilter
+	return resp, nil
+}
+
+func (s *MemoryServer) GetPing(ctx context.Context, request GetPingRequestObject) (GetPingResponseObject, error) {
+	return GetPing200JSONResponse{Message: "pong"}, nil
+}
+
+func (s *MemoryServer) PostUpdateDocument(ctx context.Context, request PostUpdateDocumentRequestObject) (PostUpdateDocumentResponseObject, error) {
+	body := request.Body
+	if body == nil || (body.Type != Tos && body.Type != PrivacyPolicy) {
+		return PostUpdateDocument400JSONResponse{Error: "BadRequest", Message: message("type must be tos or privacy_policy")}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[body.Type] = PostUpdateDocumentJSONBody(*body)
+	return PostUpdateDocument200JSONResponse{Type: PostUpdateDocument200JSONResponseType(body.Type), Content: body.Content, Url: body.Url}, nil
+}
@@ -0,0 +1,2014 @@
+//go:build go1.22
+
+// Package server provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+)
+
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+)
+
+// Defines values for AddPostReasonParamType.
+const (
+	AddPostReasonParamTypeAppBskyFeedDefsSkeletonReasonPin    AddPostReasonParamType = "app.bsky.feed.defs#skeletonReasonPin"
+	AddPostReasonParamTypeAppBskyFeedDefsSkeletonReasonRepost AddPostReasonParamType = "app.bsky.feed.defs#skeletonReasonRepost"
+)
+
+// Defines values for BatchAddPostReasonParamType.
+const (
+	BatchAddPostReasonParamTypeAppBskyFeedDefsSkeletonReasonPin    BatchAddPostReasonParamType = "app.bsky.feed.defs#skeletonReasonPin"
+	BatchAddPostReasonParamTypeAppBskyFeedDefsSkeletonReasonRepost BatchAddPostReasonParamType = "app.bsky.feed.defs#skeletonReasonRepost"
+)
+
+// Defines values for PostUpdateDocumentJSONBodyType.
+const (
+	PrivacyPolicy PostUpdateDocumentJSONBodyType = "privacy_policy"
+	Tos           PostUpdateDocumentJSONBodyType = "tos"
+)
+
+// AddPostPostParam defines model for AddPostPostParam.
+type AddPostPostParam struct {
+	Cid string `json:"cid"`
+
+	// FeedContext Context passed through to the client and feed generator.
+	FeedContext *string    `json:"feedContext,omitempty"`
+	IndexedAt   *time.Time `json:"indexedAt,omitempty"`
+	Languages   *[]string  `json:"languages"`
+
+	// Reason Reason for including the post in the feed skeleton. Currently only 'repost' reason is supported.
+	Reason *AddPostReasonParam `json:"reason,omitempty"`
+	Uri    string              `json:"uri"`
+}
+
+// AddPostReasonParam Reason for including the post in the feed skeleton. Currently only 'repost' reason is supported.
+type AddPostReasonParam struct {
+	Type AddPostReasonParamType `json:"$type"`
+
+	// Repost Repost uri for repost type.
+	Repost *string `json:"repost,omitempty"`
+}
+
+// AddPostReasonParamType defines model for AddPostReasonParam.Type.
+type AddPostReasonParamType string
+
+// BatchAddPostPostParam defines model for BatchAddPostPostParam.
+type BatchAddPostPostParam struct {
+	Cid string `json:"cid"`
+
+	// FeedContext Context passed through to the client and feed generator.
+	FeedContext *string    `json:"feedContext,omitempty"`
+	IndexedAt   *time.Time `json:"indexedAt,omitempty"`
+	Languages   *[]string  `json:"languages"`
+
+	// Reason Reason for including the post in the feed skeleton. Currently only 'repost' reason is supported.
+	Reason *BatchAddPostReasonParam `json:"reason,omitempty"`
+	Uri    string                   `json:"uri"`
+}
+
+// BatchAddPostReasonParam Reason for including the post in the feed skeleton. Currently only 'repost' reason is supported.
+type BatchAddPostReasonParam struct {
+	Type BatchAddPostReasonParamType `json:"$type"`
+
+	// Repost Repost uri for repost type.
+	Repost *string `json:"repost,omitempty"`
+}
+
+// BatchAddPostReasonParamType defines model for BatchAddPostReasonParam.Type.
+type BatchAddPostReasonParamType string
+
+// BatchAddPostsEntriesParam defines model for BatchAddPostsEntriesParam.
+type BatchAddPostsEntriesParam = []struct {
+	Feed  string                  `json:"feed"`
+	Posts []BatchAddPostPostParam `json:"posts"`
+}
+
+// BatchRemovePostPostParam defines model for BatchRemovePostPostParam.
+type BatchRemovePostPostParam struct {
+	IndexedAt *time.Time `json:"indexedAt,omitempty"`
+	Uri       string     `json:"uri"`
+}
+
+// BatchRemovePostsEntriesParam defines model for BatchRemovePostsEntriesParam.
+type BatchRemovePostsEntriesParam = []struct {
+	Feed  string                     `json:"feed"`
+	Posts []BatchRemovePostPostParam `json:"posts"`
+}
+
+// RemovePostPostParam defines model for removePostPostParam.
+type RemovePostPostParam struct {
+	IndexedAt *time.Time `json:"indexedAt,omitempty"`
+	Uri       string     `json:"uri"`
+}
+
+// PostAddPostJSONBody defines parameters for PostAddPost.
+type PostAddPostJSONBody struct {
+	Feed string           `json:"feed"`
+	Post AddPostPostParam `json:"post"`
+}
+
+// PostBatchAddPostsJSONBody defines parameters for PostBatchAddPosts.
+type PostBatchAddPostsJSONBody struct {
+	Entries BatchAddPostsEntriesParam `json:"entries"`
+}
+
+// PostBatchRemovePostsJSONBody defines parameters for PostBatchRemovePosts.
+type PostBatchRemovePostsJSONBody struct {
+	Entries BatchRemovePostsEntriesParam `json:"entries"`
+}
+
+// GetGetPostsParams defines parameters for GetGetPosts.
+type GetGetPostsParams struct {
+	Feed   string  `form:"feed" json:"feed"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// PostRegisterFeedJSONBody defines parameters for PostRegisterFeed.
+type PostRegisterFeedJSONBody struct {
+	IsActive   *bool  `json:"isActive,omitempty"`
+	LangFilter *bool  `json:"langFilter,omitempty"`
+	Uri        string `json:"uri"`
+}
+
+// PostRemovePostJSONBody defines parameters for PostRemovePost.
+type PostRemovePostJSONBody struct {
+	Feed string              `json:"feed"`
+	Post RemovePostPostParam `json:"post"`
+}
+
+// PostRemovePostByAuthorJSONBody defines parameters for PostRemovePostByAuthor.
+type PostRemovePostByAuthorJSONBody struct {
+	Author string `json:"author"`
+	Feed   string `json:"feed"`
+}
+
+// PostTrimFeedJSONBody defines parameters for PostTrimFeed.
+type PostTrimFeedJSONBody struct {
+	Feed string `json:"feed"`
+
+	// Remain Number of posts remain in the feed.
+	Remain int `json:"remain"`
+}
+
+// PostUnregisterFeedJSONBody defines parameters for PostUnregisterFeed.
+type PostUnregisterFeedJSONBody struct {
+	Uri string `json:"uri"`
+}
+
+// PostUpdateFeedJSONBody defines parameters for PostUpdateFeed.
+type PostUpdateFeedJSONBody struct {
+	IsActive   *bool  `json:"isActive,omitempty"`
+	LangFilter *bool  `json:"langFilter,omitempty"`
+	Uri        string `json:"uri"`
+}
+
+// PostUpdateDocumentJSONBody defines parameters for PostUpdateDocument.
+type PostUpdateDocumentJSONBody struct {
+	Content *string                        `json:"content"`
+	Type    PostUpdateDocumentJSONBodyType `json:"type"`
+	Url     *string                        `json:"url"`
+}
+
+// PostUpdateDocumentJSONBodyType defines parameters for PostUpdateDocument.
+type PostUpdateDocumentJSONBodyType string
+
+// PostAddPostJSONRequestBody defines body for PostAddPost for application/json ContentType.
+type PostAddPostJSONRequestBody PostAddPostJSONBody
+
+// PostBatchAddPostsJSONRequestBody defines body for PostBatchAddPosts for application/json ContentType.
+type PostBatchAddPostsJSONRequestBody PostBatchAddPostsJSONBody
+
+// PostBatchRemovePostsJSONRequestBody defines body for PostBatchRemovePosts for application/json ContentType.
+type PostBatchRemovePostsJSONRequestBody PostBatchRemovePostsJSONBody
+
+// PostRegisterFeedJSONRequestBody defines body for PostRegisterFeed for application/json ContentType.
+type PostRegisterFeedJSONRequestBody PostRegisterFeedJSONBody
+
+// PostRemovePostJSONRequestBody defines body for PostRemovePost for application/json ContentType.
+type PostRemovePostJSONRequestBody PostRemovePostJSONBody
+
+// PostRemovePostByAuthorJSONRequestBody defines body for PostRemovePostByAuthor for application/json ContentType.
+type PostRemovePostByAuthorJSONRequestBody PostRemovePostByAuthorJSONBody
+
+// PostTrimFeedJSONRequestBody defines body for PostTrimFeed for application/json ContentType.
+type PostTrimFeedJSONRequestBody PostTrimFeedJSONBody
+
+// PostUnregisterFeedJSONRequestBody defines body for PostUnregisterFeed for application/json ContentType.
+type PostUnregisterFeedJSONRequestBody PostUnregisterFeedJSONBody
+
+// PostUpdateFeedJSONRequestBody defines body for PostUpdateFeed for application/json ContentType.
+type PostUpdateFeedJSONRequestBody PostUpdateFeedJSONBody
+
+// PostUpdateDocumentJSONRequestBody defines body for PostUpdateDocument for application/json ContentType.
+type PostUpdateDocumentJSONRequestBody PostUpdateDocumentJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Add new post to feed
+	// (POST /api/feed/addPost)
+	PostAddPost(w http.ResponseWriter, r *http.Request)
+	// Add multiple posts to multiple feeds
+	// (POST /api/feed/batchAddPosts)
+	PostBatchAddPosts(w http.ResponseWriter, r *http.Request)
+	// Remove multiple posts from multiple feeds
+	// (POST /api/feed/batchRemovePosts)
+	PostBatchRemovePosts(w http.ResponseWriter, r *http.Request)
+	// Get posts from a feed
+	// (GET /api/feed/getPosts)
+	GetGetPosts(w http.ResponseWriter, r *http.Request, params GetGetPostsParams)
+	// Get feed list
+	// (GET /api/feed/listFeeds)
+	GetListFeeds(w http.ResponseWriter, r *http.Request)
+	// Register new feed
+	// (POST /api/feed/registerFeed)
+	PostRegisterFeed(w http.ResponseWriter, r *http.Request)
+	// Remove a post from a feed
+	// (POST /api/feed/removePost)
+	PostRemovePost(w http.ResponseWriter, r *http.Request)
+	// Remove all posts by a specific author from a feed
+	// (POST /api/feed/removePostByAuthor)
+	PostRemovePostByAuthor(w http.ResponseWriter, r *http.Request)
+	// Remove a post from a feed
+	// (POST /api/feed/trimPosts)
+	PostTrimFeed(w http.ResponseWriter, r *http.Request)
+	// Unregister a feed
+	// (POST /api/feed/unregisterFeed)
+	PostUnregisterFeed(w http.ResponseWriter, r *http.Request)
+	// Update feed setting
+	// (POST /api/feed/updateFeed)
+	PostUpdateFeed(w http.ResponseWriter, r *http.Request)
+	// Ping system
+	// (GET /api/gyoka/ping)
+	GetPing(w http.ResponseWriter, r *http.Request)
+	// Update document content and URL
+	// (POST /api/gyoka/updateDocument)
+	PostUpdateDocument(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// PostAddPost operation middleware
+func (siw *ServerInterfaceWrapper) PostAddPost(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAddPost(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostBatchAddPosts operation middleware
+func (siw *ServerInterfaceWrapper) PostBatchAddPosts(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBatchAddPosts(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostBatchRemovePosts operation middleware
+func (siw *ServerInterfaceWrapper) PostBatchRemovePosts(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBatchRemovePosts(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetGetPosts operation middleware
+func (siw *ServerInterfaceWrapper) GetGetPosts(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGetPostsParams
+
+	// ------------- Required query parameter "feed" -------------
+
+	if paramValue := r.URL.Query().Get("feed"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "feed"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "feed", r.URL.Query(), &params.Feed)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feed", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetPosts(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetListFeeds operation middleware
+func (siw *ServerInterfaceWrapper) GetListFeeds(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetListFeeds(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostRegisterFeed operation middleware
+func (siw *ServerInterfaceWrapper) PostRegisterFeed(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRegisterFeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostRemovePost operation middleware
+func (siw *ServerInterfaceWrapper) PostRemovePost(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRemovePost(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostRemovePostByAuthor operation middleware
+func (siw *ServerInterfaceWrapper) PostRemovePostByAuthor(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRemovePostByAuthor(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTrimFeed operation middleware
+func (siw *ServerInterfaceWrapper) PostTrimFeed(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTrimFeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostUnregisterFeed operation middleware
+func (siw *ServerInterfaceWrapper) PostUnregisterFeed(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUnregisterFeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostUpdateFeed operation middleware
+func (siw *ServerInterfaceWrapper) PostUpdateFeed(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUpdateFeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPing operation middleware
+func (siw *ServerInterfaceWrapper) GetPing(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPing(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostUpdateDocument operation middleware
+func (siw *ServerInterfaceWrapper) PostUpdateDocument(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUpdateDocument(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/addPost", wrapper.PostAddPost)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/batchAddPosts", wrapper.PostBatchAddPosts)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/batchRemovePosts", wrapper.PostBatchRemovePosts)
+	m.HandleFunc("GET "+options.BaseURL+"/api/feed/getPosts", wrapper.GetGetPosts)
+	m.HandleFunc("GET "+options.BaseURL+"/api/feed/listFeeds", wrapper.GetListFeeds)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/registerFeed", wrapper.PostRegisterFeed)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/removePost", wrapper.PostRemovePost)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/removePostByAuthor", wrapper.PostRemovePostByAuthor)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/trimPosts", wrapper.PostTrimFeed)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/unregisterFeed", wrapper.PostUnregisterFeed)
+	m.HandleFunc("POST "+options.BaseURL+"/api/feed/updateFeed", wrapper.PostUpdateFeed)
+	m.HandleFunc("GET "+options.BaseURL+"/api/gyoka/ping", wrapper.GetPing)
+	m.HandleFunc("POST "+options.BaseURL+"/api/gyoka/updateDocument", wrapper.PostUpdateDocument)
+
+	return m
+}
+
+type PostAddPostRequestObject struct {
+	Body *PostAddPostJSONRequestBody
+}
+
+type PostAddPostResponseObject interface {
+	VisitPostAddPostResponse(w http.ResponseWriter) error
+}
+
+type PostAddPost200JSONResponse struct {
+	Feed    string `json:"feed"`
+	Message string `json:"message"`
+	Post    struct {
+		Cid string `json:"cid"`
+
+		// FeedContext Context passed through to the client and feed generator.
+		FeedContext *string   `json:"feedContext,omitempty"`
+		IndexedAt   time.Time `json:"indexedAt"`
+		Languages   []string  `json:"languages"`
+
+		// Reason Reason for including the post in the feed skeleton. Currently only 'repost' reason is supported.
+		Reason *struct {
+			Type PostAddPost200JSONResponsePostReasonType `json:"$type"`
+
+			// Repost Repost uri for repost type.
+			Repost *string `json:"repost,omitempty"`
+		} `json:"reason,omitempty"`
+		Uri string `json:"uri"`
+	} `json:"post"`
+}
+
+func (response PostAddPost200JSONResponse) VisitPostAddPostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAddPost400JSONResponse struct {
+	Error   PostAddPost400JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response PostAddPost400JSONResponse) VisitPostAddPostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAddPost401JSONResponse struct {
+	Error   PostAddPost401JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response PostAddPost401JSONResponse) VisitPostAddPostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAddPost404JSONResponse struct {
+	Error   PostAddPost404JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response PostAddPost404JSONResponse) VisitPostAddPostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAddPost500JSONResponse struct {
+	Error   PostAddPost500JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response PostAddPost500JSONResponse) VisitPostAddPostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchAddPostsRequestObject struct {
+	Body *PostBatchAddPostsJSONRequestBody
+}
+
+type PostBatchAddPostsResponseObject interface {
+	VisitPostBatchAddPostsResponse(w http.ResponseWriter) error
+}
+
+type PostBatchAddPosts200JSONResponse struct {
+	Results []struct {
+		Feed    string `json:"feed"`
+		Results []struct {
+			Error  *string                                              `json:"error,omitempty"`
+			Status PostBatchAddPosts200JSONResponseResultsResultsStatus `json:"status"`
+			Uri    string                                               `json:"uri"`
+		} `json:"results"`
+	} `json:"results"`
+}
+
+func (response PostBatchAddPosts200JSONResponse) VisitPostBatchAddPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchAddPosts400JSONResponse struct {
+	Error   PostBatchAddPosts400JSONResponseError `json:"error"`
+	Message *string                               `json:"message,omitempty"`
+}
+
+func (response PostBatchAddPosts400JSONResponse) VisitPostBatchAddPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchAddPosts401JSONResponse struct {
+	Error   PostBatchAddPosts401JSONResponseError `json:"error"`
+	Message *string                               `json:"message,omitempty"`
+}
+
+func (response PostBatchAddPosts401JSONResponse) VisitPostBatchAddPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchAddPosts500JSONResponse struct {
+	Error   PostBatchAddPosts500JSONResponseError `json:"error"`
+	Message *string                               `json:"message,omitempty"`
+}
+
+func (response PostBatchAddPosts500JSONResponse) VisitPostBatchAddPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchRemovePostsRequestObject struct {
+	Body *PostBatchRemovePostsJSONRequestBody
+}
+
+type PostBatchRemovePostsResponseObject interface {
+	VisitPostBatchRemovePostsResponse(w http.ResponseWriter) error
+}
+
+type PostBatchRemovePosts200JSONResponse struct {
+	Results []struct {
+		Feed    string `json:"feed"`
+		Results []struct {
+			Error  *string                                                 `json:"error,omitempty"`
+			Status PostBatchRemovePosts200JSONResponseResultsResultsStatus `json:"status"`
+			Uri    string                                                  `json:"uri"`
+		} `json:"results"`
+	} `json:"results"`
+}
+
+func (response PostBatchRemovePosts200JSONResponse) VisitPostBatchRemovePostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchRemovePosts400JSONResponse struct {
+	Error   PostBatchRemovePosts400JSONResponseError `json:"error"`
+	Message *string                                  `json:"message,omitempty"`
+}
+
+func (response PostBatchRemovePosts400JSONResponse) VisitPostBatchRemovePostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchRemovePosts401JSONResponse struct {
+	Error   PostBatchRemovePosts401JSONResponseError `json:"error"`
+	Message *string                                  `json:"message,omitempty"`
+}
+
+func (response PostBatchRemovePosts401JSONResponse) VisitPostBatchRemovePostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostBatchRemovePosts500JSONResponse struct {
+	Error   PostBatchRemovePosts500JSONResponseError `json:"error"`
+	Message *string                                  `json:"message,omitempty"`
+}
+
+func (response PostBatchRemovePosts500JSONResponse) VisitPostBatchRemovePostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGetPostsRequestObject struct {
+	Params GetGetPostsParams
+}
+
+type GetGetPostsResponseObject interface {
+	VisitGetGetPostsResponse(w http.ResponseWriter) error
+}
+
+type GetGetPosts200JSONResponse struct {
+	Cursor *string `json:"cursor,omitempty"`
+	Feed   string  `json:"feed"`
+	Posts  []struct {
+		Cid         string    `json:"cid"`
+		FeedContext *string   `json:"feedContext,omitempty"`
+		IndexedAt   time.Time `json:"indexedAt"`
+
+		// Langs Deprecated alias of languages. Use languages instead.
+		// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
+		Langs     *[]string `json:"langs,omitempty"`
+		Languages []string  `json:"languages"`
+		Reason    *struct {
+			Repost string `json:"repost"`
+		} `json:"reason,omitempty"`
+		Uri string `json:"uri"`
+	} `json:"posts"`
+}
+
+func (response GetGetPosts200JSONResponse) VisitGetGetPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGetPosts400JSONResponse struct {
+	Error   GetGetPosts400JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response GetGetPosts400JSONResponse) VisitGetGetPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGetPosts401JSONResponse struct {
+	Error   GetGetPosts401JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response GetGetPosts401JSONResponse) VisitGetGetPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGetPosts404JSONResponse struct {
+	Error   GetGetPosts404JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response GetGetPosts404JSONResponse) VisitGetGetPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGetPosts500JSONResponse struct {
+	Error   GetGetPosts500JSONResponseError `json:"error"`
+	Message *string                         `json:"message,omitempty"`
+}
+
+func (response GetGetPosts500JSONResponse) VisitGetGetPostsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetListFeedsRequestObject struct {
+}
+
+type GetListFeedsResponseObject interface {
+	VisitGetListFeedsResponse(w http.ResponseWriter) error
+}
+
+type GetListFeeds200JSONResponse struct {
+	Feeds []struct {
+		IsActive   bool   `json:"isActive"`
+		LangFilter bool   `json:"langFilter"`
+		Uri        string `json:"uri"`
+	} `json:"feeds"`
+}
+
+func (response GetListFeeds200JSONResponse) VisitGetListFeedsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetListFeeds401JSONResponse struct {
+	Error   GetListFeeds401JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response GetListFeeds401JSONResponse) VisitGetListFeedsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetListFeeds500JSONResponse struct {
+	Error   GetListFeeds500JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response GetListFeeds500JSONResponse) VisitGetListFeedsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterFeedRequestObject struct {
+	Body *PostRegisterFeedJSONRequestBody
+}
+
+type PostRegisterFeedResponseObject interface {
+	VisitPostRegisterFeedResponse(w http.ResponseWriter) error
+}
+
+type PostRegisterFeed200JSONResponse struct {
+	Feed struct {
+		IsActive   bool   `json:"isActive"`
+		LangFilter bool   `json:"langFilter"`
+		Uri        string `json:"uri"`
+	} `json:"feed"`
+	Message string `json:"message"`
+}
+
+func (response PostRegisterFeed200JSONResponse) VisitPostRegisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterFeed400JSONResponse struct {
+	Error   PostRegisterFeed400JSONResponseError `json:"error"`
+	Message *string                              `json:"message,omitempty"`
+}
+
+func (response PostRegisterFeed400JSONResponse) VisitPostRegisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterFeed401JSONResponse struct {
+	Error   PostRegisterFeed401JSONResponseError `json:"error"`
+	Message *string                              `json:"message,omitempty"`
+}
+
+func (response PostRegisterFeed401JSONResponse) VisitPostRegisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterFeed409JSONResponse struct {
+	Error   PostRegisterFeed409JSONResponseError `json:"error"`
+	Message *string                              `json:"message,omitempty"`
+}
+
+func (response PostRegisterFeed409JSONResponse) VisitPostRegisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterFeed500JSONResponse struct {
+	Error   PostRegisterFeed500JSONResponseError `json:"error"`
+	Message *string                              `json:"message,omitempty"`
+}
+
+func (response PostRegisterFeed500JSONResponse) VisitPostRegisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostRequestObject struct {
+	Body *PostRemovePostJSONRequestBody
+}
+
+type PostRemovePostResponseObject interface {
+	VisitPostRemovePostResponse(w http.ResponseWriter) error
+}
+
+type PostRemovePost200JSONResponse struct {
+	Feed    string `json:"feed"`
+	Message string `json:"message"`
+	Post    struct {
+		IndexedAt time.Time `json:"indexedAt"`
+		Uri       string    `json:"uri"`
+	} `json:"post"`
+}
+
+func (response PostRemovePost200JSONResponse) VisitPostRemovePostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePost400JSONResponse struct {
+	Error   PostRemovePost400JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostRemovePost400JSONResponse) VisitPostRemovePostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePost401JSONResponse struct {
+	Error   PostRemovePost401JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostRemovePost401JSONResponse) VisitPostRemovePostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePost404JSONResponse struct {
+	Error   PostRemovePost404JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostRemovePost404JSONResponse) VisitPostRemovePostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePost500JSONResponse struct {
+	Error   PostRemovePost500JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostRemovePost500JSONResponse) VisitPostRemovePostResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostByAuthorRequestObject struct {
+	Body *PostRemovePostByAuthorJSONRequestBody
+}
+
+type PostRemovePostByAuthorResponseObject interface {
+	VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error
+}
+
+type PostRemovePostByAuthor200JSONResponse struct {
+	Author       string `json:"author"`
+	DeletedCount int    `json:"deletedCount"`
+	Feed         string `json:"feed"`
+	Message      string `json:"message"`
+}
+
+func (response PostRemovePostByAuthor200JSONResponse) VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostByAuthor400JSONResponse struct {
+	Error   PostRemovePostByAuthor400JSONResponseError `json:"error"`
+	Message *string                                    `json:"message,omitempty"`
+}
+
+func (response PostRemovePostByAuthor400JSONResponse) VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostByAuthor401JSONResponse struct {
+	Error   PostRemovePostByAuthor401JSONResponseError `json:"error"`
+	Message *string                                    `json:"message,omitempty"`
+}
+
+func (response PostRemovePostByAuthor401JSONResponse) VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostByAuthor404JSONResponse struct {
+	Error   PostRemovePostByAuthor404JSONResponseError `json:"error"`
+	Message *string                                    `json:"message,omitempty"`
+}
+
+func (response PostRemovePostByAuthor404JSONResponse) VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRemovePostByAuthor500JSONResponse struct {
+	Error   PostRemovePostByAuthor500JSONResponseError `json:"error"`
+	Message *string                                    `json:"message,omitempty"`
+}
+
+func (response PostRemovePostByAuthor500JSONResponse) VisitPostRemovePostByAuthorResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTrimFeedRequestObject struct {
+	Body *PostTrimFeedJSONRequestBody
+}
+
+type PostTrimFeedResponseObject interface {
+	VisitPostTrimFeedResponse(w http.ResponseWriter) error
+}
+
+type PostTrimFeed200JSONResponse struct {
+	DeletedCount float32 `json:"deletedCount"`
+	Feed         string  `json:"feed"`
+	Message      string  `json:"message"`
+}
+
+func (response PostTrimFeed200JSONResponse) VisitPostTrimFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTrimFeed400JSONResponse struct {
+	Error   PostTrimFeed400JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response PostTrimFeed400JSONResponse) VisitPostTrimFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTrimFeed401JSONResponse struct {
+	Error   PostTrimFeed401JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response PostTrimFeed401JSONResponse) VisitPostTrimFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTrimFeed404JSONResponse struct {
+	Error   PostTrimFeed404JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response PostTrimFeed404JSONResponse) VisitPostTrimFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostTrimFeed500JSONResponse struct {
+	Error   PostTrimFeed500JSONResponseError `json:"error"`
+	Message *string                          `json:"message,omitempty"`
+}
+
+func (response PostTrimFeed500JSONResponse) VisitPostTrimFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUnregisterFeedRequestObject struct {
+	Body *PostUnregisterFeedJSONRequestBody
+}
+
+type PostUnregisterFeedResponseObject interface {
+	VisitPostUnregisterFeedResponse(w http.ResponseWriter) error
+}
+
+type PostUnregisterFeed200JSONResponse struct {
+	Message string `json:"message"`
+}
+
+func (response PostUnregisterFeed200JSONResponse) VisitPostUnregisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUnregisterFeed400JSONResponse struct {
+	Error   PostUnregisterFeed400JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUnregisterFeed400JSONResponse) VisitPostUnregisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUnregisterFeed401JSONResponse struct {
+	Error   PostUnregisterFeed401JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUnregisterFeed401JSONResponse) VisitPostUnregisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUnregisterFeed404JSONResponse struct {
+	Error   PostUnregisterFeed404JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUnregisterFeed404JSONResponse) VisitPostUnregisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUnregisterFeed500JSONResponse struct {
+	Error   PostUnregisterFeed500JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUnregisterFeed500JSONResponse) VisitPostUnregisterFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateFeedRequestObject struct {
+	Body *PostUpdateFeedJSONRequestBody
+}
+
+type PostUpdateFeedResponseObject interface {
+	VisitPostUpdateFeedResponse(w http.ResponseWriter) error
+}
+
+type PostUpdateFeed200JSONResponse struct {
+	Feed struct {
+		IsActive   bool   `json:"isActive"`
+		LangFilter bool   `json:"langFilter"`
+		Uri        string `json:"uri"`
+	} `json:"feed"`
+	Message string `json:"message"`
+}
+
+func (response PostUpdateFeed200JSONResponse) VisitPostUpdateFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateFeed400JSONResponse struct {
+	Error   PostUpdateFeed400JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostUpdateFeed400JSONResponse) VisitPostUpdateFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateFeed401JSONResponse struct {
+	Error   PostUpdateFeed401JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostUpdateFeed401JSONResponse) VisitPostUpdateFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateFeed404JSONResponse struct {
+	Error   PostUpdateFeed404JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostUpdateFeed404JSONResponse) VisitPostUpdateFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateFeed500JSONResponse struct {
+	Error   PostUpdateFeed500JSONResponseError `json:"error"`
+	Message *string                            `json:"message,omitempty"`
+}
+
+func (response PostUpdateFeed500JSONResponse) VisitPostUpdateFeedResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPingRequestObject struct {
+}
+
+type GetPingResponseObject interface {
+	VisitGetPingResponse(w http.ResponseWriter) error
+}
+
+type GetPing200JSONResponse struct {
+	Message string `json:"message"`
+}
+
+func (response GetPing200JSONResponse) VisitGetPingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPing401JSONResponse struct {
+	Error   GetPing401JSONResponseError `json:"error"`
+	Message *string                     `json:"message,omitempty"`
+}
+
+func (response GetPing401JSONResponse) VisitGetPingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPing500JSONResponse struct {
+	Error   GetPing500JSONResponseError `json:"error"`
+	Message *string                     `json:"message,omitempty"`
+}
+
+func (response GetPing500JSONResponse) VisitGetPingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateDocumentRequestObject struct {
+	Body *PostUpdateDocumentJSONRequestBody
+}
+
+type PostUpdateDocumentResponseObject interface {
+	VisitPostUpdateDocumentResponse(w http.ResponseWriter) error
+}
+
+type PostUpdateDocument200JSONResponse struct {
+	Content *string                               `json:"content"`
+	Type    PostUpdateDocument200JSONResponseType `json:"type"`
+	Url     *string                               `json:"url"`
+}
+
+func (response PostUpdateDocument200JSONResponse) VisitPostUpdateDocumentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateDocument400JSONResponse struct {
+	Error   PostUpdateDocument400JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUpdateDocument400JSONResponse) VisitPostUpdateDocumentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateDocument401JSONResponse struct {
+	Error   PostUpdateDocument401JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUpdateDocument401JSONResponse) VisitPostUpdateDocumentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostUpdateDocument500JSONResponse struct {
+	Error   PostUpdateDocument500JSONResponseError `json:"error"`
+	Message *string                                `json:"message,omitempty"`
+}
+
+func (response PostUpdateDocument500JSONResponse) VisitPostUpdateDocumentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Add new post to feed
+	// (POST /api/feed/addPost)
+	PostAddPost(ctx context.Context, request PostAddPostRequestObject) (PostAddPostResponseObject, error)
+	// Add multiple posts to multiple feeds
+	// (POST /api/feed/batchAddPosts)
+	PostBatchAddPosts(ctx context.Context, request PostBatchAddPostsRequestObject) (PostBatchAddPostsResponseObject, error)
+	// Remove multiple posts from multiple feeds
+	// (POST /api/feed/batchRemovePosts)
+	PostBatchRemovePosts(ctx context.Context, request PostBatchRemovePostsRequestObject) (PostBatchRemovePostsResponseObject, error)
+	// Get posts from a feed
+	// (GET /api/feed/getPosts)
+	GetGetPosts(ctx context.Context, request GetGetPostsRequestObject) (GetGetPostsResponseObject, error)
+	// Get feed list
+	// (GET /api/feed/listFeeds)
+	GetListFeeds(ctx context.Context, request GetListFeedsRequestObject) (GetListFeedsResponseObject, error)
+	// Register new feed
+	// (POST /api/feed/registerFeed)
+	PostRegisterFeed(ctx context.Context, request PostRegisterFeedRequestObject) (PostRegisterFeedResponseObject, error)
+	// Remove a post from a feed
+	// (POST /api/feed/removePost)
+	PostRemovePost(ctx context.Context, request PostRemovePostRequestObject) (PostRemovePostResponseObject, error)
+	// Remove all posts by a specific author from a feed
+	// (POST /api/feed/removePostByAuthor)
+	PostRemovePostByAuthor(ctx context.Context, request PostRemovePostByAuthorRequestObject) (PostRemovePostByAuthorResponseObject, error)
+	// Remove a post from a feed
+	// (POST /api/feed/trimPosts)
+	PostTrimFeed(ctx context.Context, request PostTrimFeedRequestObject) (PostTrimFeedResponseObject, error)
+	// Unregister a feed
+	// (POST /api/feed/unregisterFeed)
+	PostUnregisterFeed(ctx context.Context, request PostUnregisterFeedRequestObject) (PostUnregisterFeedResponseObject, error)
+	// Update feed setting
+	// (POST /api/feed/updateFeed)
+	PostUpdateFeed(ctx context.Context, request PostUpdateFeedRequestObject) (PostUpdateFeedResponseObject, error)
+	// Ping system
+	// (GET /api/gyoka/ping)
+	GetPing(ctx context.Context, request GetPingRequestObject) (GetPingResponseObject, error)
+	// Update document content and URL
+	// (POST /api/gyoka/updateDocument)
+	PostUpdateDocument(ctx context.Context, request PostUpdateDocumentRequestObject) (PostUpdateDocumentResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// PostAddPost operation middleware
+func (sh *strictHandler) PostAddPost(w http.ResponseWriter, r *http.Request) {
+	var request PostAddPostRequestObject
+
+	var body PostAddPostJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAddPost(ctx, request.(PostAddPostRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAddPost")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAddPostResponseObject); ok {
+		if err := validResponse.VisitPostAddPostResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostBatchAddPosts operation middleware
+func (sh *strictHandler) PostBatchAddPosts(w http.ResponseWriter, r *http.Request) {
+	var request PostBatchAddPostsRequestObject
+
+	var body PostBatchAddPostsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostBatchAddPosts(ctx, request.(PostBatchAddPostsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostBatchAddPosts")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostBatchAddPostsResponseObject); ok {
+		if err := validResponse.VisitPostBatchAddPostsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostBatchRemovePosts operation middleware
+func (sh *strictHandler) PostBatchRemovePosts(w http.ResponseWriter, r *http.Request) {
+	var request PostBatchRemovePostsRequestObject
+
+	var body PostBatchRemovePostsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostBatchRemovePosts(ctx, request.(PostBatchRemovePostsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostBatchRemovePosts")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostBatchRemovePostsResponseObject); ok {
+		if err := validResponse.VisitPostBatchRemovePostsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetGetPosts operation middleware
+func (sh *strictHandler) GetGetPosts(w http.ResponseWriter, r *http.Request, params GetGetPostsParams) {
+	var request GetGetPostsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGetPosts(ctx, request.(GetGetPostsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGetPosts")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetGetPostsResponseObject); ok {
+		if err := validResponse.VisitGetGetPostsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetListFeeds operation middleware
+func (sh *strictHandler) GetListFeeds(w http.ResponseWriter, r *http.Request) {
+	var request GetListFeedsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetListFeeds(ctx, request.(GetListFeedsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetListFeeds")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetListFeedsResponseObject); ok {
+		if err := validResponse.VisitGetListFeedsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRegisterFeed operation middleware
+func (sh *strictHandler) PostRegisterFeed(w http.ResponseWriter, r *http.Request) {
+	var request PostRegisterFeedRequestObject
+
+	var body PostRegisterFeedJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRegisterFeed(ctx, request.(PostRegisterFeedRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRegisterFeed")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRegisterFeedResponseObject); ok {
+		if err := validResponse.VisitPostRegisterFeedResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRemovePost operation middleware
+func (sh *strictHandler) PostRemovePost(w http.ResponseWriter, r *http.Request) {
+	var request PostRemovePostRequestObject
+
+	var body PostRemovePostJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRemovePost(ctx, request.(PostRemovePostRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRemovePost")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRemovePostResponseObject); ok {
+		if err := validResponse.VisitPostRemovePostResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRemovePostByAuthor operation middleware
+func (sh *strictHandler) PostRemovePostByAuthor(w http.ResponseWriter, r *http.Request) {
+	var request PostRemovePostByAuthorRequestObject
+
+	var body PostRemovePostByAuthorJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRemovePostByAuthor(ctx, request.(PostRemovePostByAuthorRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRemovePostByAuthor")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRemovePostByAuthorResponseObject); ok {
+		if err := validResponse.VisitPostRemovePostByAuthorResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostTrimFeed operation middleware
+func (sh *strictHandler) PostTrimFeed(w http.ResponseWriter, r *http.Request) {
+	var request PostTrimFeedRequestObject
+
+	var body PostTrimFeedJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostTrimFeed(ctx, request.(PostTrimFeedRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostTrimFeed")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostTrimFeedResponseObject); ok {
+		if err := validResponse.VisitPostTrimFeedResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUnregisterFeed operation middleware
+func (sh *strictHandler) PostUnregisterFeed(w http.ResponseWriter, r *http.Request) {
+	var request PostUnregisterFeedRequestObject
+
+	var body PostUnregisterFeedJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUnregisterFeed(ctx, request.(PostUnregisterFeedRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUnregisterFeed")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUnregisterFeedResponseObject); ok {
+		if err := validResponse.VisitPostUnregisterFeedResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUpdateFeed operation middleware
+func (sh *strictHandler) PostUpdateFeed(w http.ResponseWriter, r *http.Request) {
+	var request PostUpdateFeedRequestObject
+
+	var body PostUpdateFeedJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUpdateFeed(ctx, request.(PostUpdateFeedRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUpdateFeed")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUpdateFeedResponseObject); ok {
+		if err := validResponse.VisitPostUpdateFeedResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetPing operation middleware
+func (sh *strictHandler) GetPing(w http.ResponseWriter, r *http.Request) {
+	var request GetPingRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetPing(ctx, request.(GetPingRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetPing")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetPingResponseObject); ok {
+		if err := validResponse.VisitGetPingResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostUpdateDocument operation middleware
+func (sh *strictHandler) PostUpdateDocument(w http.ResponseWriter, r *http.Request) {
+	var request PostUpdateDocumentRequestObject
+
+	var body PostUpdateDocumentJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostUpdateDocument(ctx, request.(PostUpdateDocumentRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostUpdateDocument")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostUpdateDocumentResponseObject); ok {
+		if err := validResponse.VisitPostUpdateDocumentResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
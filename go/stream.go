@@ -0,0 +1,175 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// errStopStream ends AllPosts when the consumer stops iterating.
+var errStopStream = errors.New("stop stream")
+
+// DecodeStream decodes a getPosts 200 response body from r and calls fn
+// for every post as soon as it is decoded, so neither the body nor the page
+// is held in memory. The returned page carries Feed and Cursor but no Posts.
+// An error from fn stops decoding and is returned as is.
+func (d *PostsDecoder) DecodeStream(r io.Reader, fn func(Post) error) (*PostsPage, error) {
+	return d.decodeStream(r, "", fn)
+}
+
+// decodeStream is DecodeStream with the feed to report language mismatches
+// for until the body names it.
+func (d *PostsDecoder) decodeStream(r io.Reader, feed string, fn func(Post) error) (*PostsPage, error) {
+	page := &PostsPage{Feed: feed}
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("decode getPosts response: %w", err)
+		}
+		switch tok {
+		case "feed":
+			err = dec.Decode(&page.Feed)
+		case "cursor":
+			err = dec.Decode(&page.Cursor)
+		case "posts":
+			if err := d.streamPosts(dec, page, fn); err != nil {
+				return nil, err
+			}
+		default:
+			err = dec.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode getPosts response: %w", err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if page.Cursor != nil && *page.Cursor == "" {
+		page.Cursor = nil
+	}
+	return page, nil
+}
+
+func (d *PostsDecoder) streamPosts(dec *json.Decoder, page *PostsPage, fn func(Post) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode getPosts response: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("decode getPosts response: posts is %v, not an array", tok)
+	}
+	for dec.More() {
+		var rp rawPost
+		if err := dec.Decode(&rp); err != nil {
+			return fmt.Errorf("decode getPosts response: %w", err)
+		}
+		p, err := d.post(page.Feed, rp)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode getPosts response: %w", err)
+	}
+	if tok != want {
+		return fmt.Errorf("decode getPosts response: got %v, want %v", tok, want)
+	}
+	return nil
+}
+
+// StreamPosts fetches one page of posts and calls fn for every post while
+// the response body is read, like DecodeStream. The returned page carries
+// the cursor of the next page. Non-200 responses are returned as *APIError.
+//
+// The body is only streamed from clients that expose the raw GetGetPosts
+// method: *ClientWithResponses, and wrappers with an Unwrap method leading
+// to one, such as SafeClient and Coalescer. Other clients are read through
+// GetGetPostsWithResponse, which buffers the body. Among the doer options,
+// WithMetrics, WithTracing, WithLogging below debug level and WithAuditLog
+// leave 200 getPosts bodies unread; WithLogging at debug level and a
+// recording Cassette read the whole body before it reaches StreamPosts.
+func (d *PostsDecoder) StreamPosts(ctx context.Context, c ClientWithResponsesInterface, params GetGetPostsParams, fn func(Post) error) (*PostsPage, error) {
+	rc, ok := rawGetPosts(c)
+	if !ok {
+		resp, err := c.GetGetPostsWithResponse(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, newAPIError(OpGetGetPosts, resp.HTTPResponse, resp.Body)
+		}
+		return d.decodeStream(bytes.NewReader(resp.Body), params.Feed, fn)
+	}
+	rsp, err := rc.GetGetPosts(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(rsp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(OpGetGetPosts, rsp, body)
+	}
+	return d.decodeStream(rsp.Body, params.Feed, fn)
+}
+
+// AllPosts iterates over every post of feed, newest first, following
+// cursors. Each page is read with StreamPosts, so memory stays flat however
+// large the feed is when the body is streamed; see StreamPosts for the
+// clients that buffer it. Iteration stops after the first error,
+// which is yielded with a zero Post.
+func (d *PostsDecoder) AllPosts(ctx context.Context, c ClientWithResponsesInterface, feed string) iter.Seq2[Post, error] {
+	return func(yield func(Post, error) bool) {
+		limit := maxPostsPageSize
+		params := GetGetPostsParams{Feed: feed, Limit: &limit}
+		for {
+			n := 0
+			page, err := d.StreamPosts(ctx, c, params, func(p Post) error {
+				n++
+				if !yield(p, nil) {
+					return errStopStream
+				}
+				return nil
+			})
+			if errors.Is(err, errStopStream) {
+				return
+			}
+			if err != nil {
+				yield(Post{}, err)
+				return
+			}
+			if page.Cursor == nil || n == 0 {
+				return
+			}
+			params.Cursor = page.Cursor
+		}
+	}
+}
+
+// AllPosts iterates over every post of feed with a zero PostsDecoder.
+func AllPosts(ctx context.Context, c ClientWithResponsesInterface, feed string) iter.Seq2[Post, error] {
+	return (&PostsDecoder{}).AllPosts(ctx, c, feed)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutPhase is the part of a call a TimeoutError hit.
+type TimeoutPhase string
+
+const (
+	// TimeoutConnect is getting a connection, including DNS and TLS.
+	TimeoutConnect TimeoutPhase = "connect"
+	// TimeoutHeaders is sending the request and waiting for the response
+	// headers. A Doer other than *http.Client reports every timeout before
+	// the response as this phase.
+	TimeoutHeaders TimeoutPhase = "headers"
+	// TimeoutBody is reading the response body.
+	TimeoutBody TimeoutPhase = "body"
+)
+
+// TimeoutError is returned when a call set up by WithOperationTimeouts runs
+// past its deadline. It unwraps to the underlying error, so
+// errors.Is(err, context.DeadlineExceeded) holds.
+type TimeoutError struct {
+	Operation string
+	Phase     TimeoutPhase
+	// Limit is the time the call had, which is shorter than the operation
+	// timeout when the caller's context had an earlier deadline.
+	Limit time.Duration
+	Err   error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("gyoka: %s: timeout after %s while %s", e.Operation, e.Limit.Round(time.Millisecond), e.phaseText())
+}
+
+func (e *TimeoutError) phaseText() string {
+	switch e.Phase {
+	case TimeoutConnect:
+		return "connecting"
+	case TimeoutBody:
+		return "reading the body"
+	}
+	return "waiting for headers"
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// Timeout reports true, like net.Error.
+func (e *TimeoutError) Timeout() bool { return true }
+
+// DefaultOperationTimeouts are the timeouts WithOperationTimeouts uses for
+// operations missing from TimeoutOptions.Operations.
+var DefaultOperationTimeouts = map[string]time.Duration{
+	OpGetPing:                5 * time.Second,
+	OpGetListFeeds:           10 * time.Second,
+	OpGetGetPosts:            30 * time.Second,
+	OpPostAddPost:            10 * time.Second,
+	OpPostRemovePost:         10 * time.Second,
+	OpPostBatchAddPosts:      15 * time.Second,
+	OpPostBatchRemovePosts:   15 * time.Second,
+	OpPostRegisterFeed:       15 * time.Second,
+	OpPostUpdateFeed:         15 * time.Second,
+	OpPostUnregisterFeed:     60 * time.Second,
+	OpPostUpdateDocument:     30 * time.Second,
+	OpPostTrimFeed:           5 * time.Minute,
+	OpPostRemovePostByAuthor: 5 * time.Minute,
+}
+
+// TimeoutOptions configures WithOperationTimeouts.
+type TimeoutOptions struct {
+	// Operations overrides DefaultOperationTimeouts per operation ID.
+	Operations map[string]time.Duration
+	// Default applies to operations in neither map. Defaults to 30s.
+	Default time.Duration
+	// PerItem is added for every post in a batch call. Defaults to 50ms.
+	PerItem time.Duration
+}
+
+// WithOperationTimeouts gives every call a deadline from its operation's
+// timeout, derived from the caller's context so an earlier caller deadline
+// still wins. Batch calls get PerItem more per post. Timeouts are returned
+// as *TimeoutError. It must come after WithHTTPClient, and replaces a
+// blanket http.Client.Timeout.
+func WithOperationTimeouts(opts TimeoutOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.Default <= 0 {
+			opts.Default = 30 * time.Second
+		}
+		if opts.PerItem <= 0 {
+			opts.PerItem = 50 * time.Millisecond
+		}
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				return opts.do(req, next)
+			})
+		})
+		return nil
+	}
+}
+
+// timeout returns the timeout for a call of op with batchSize posts.
+func (o *TimeoutOptions) timeout(op string, batchSize int) time.Duration {
+	d, ok := o.Operations[op]
+	if !ok {
+		d, ok = DefaultOperationTimeouts[op]
+	}
+	if !ok {
+		d = o.Default
+	}
+	return d + time.Duration(batchSize)*o.PerItem
+}
+
+func (o *TimeoutOptions) do(req *http.Request, next HttpRequestDoer) (*http.Response, error) {
+	op := operationName(req)
+	var batchSize int
+	if op == OpPostBatchAddPosts || op == OpPostBatchRemovePosts {
+		batchSize = inspectRequest(req).BatchSize
+	}
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(req.Context(), o.timeout(op, batchSize))
+	deadline, _ := ctx.Deadline()
+
+	var phase atomic.Value
+	phase.Store(TimeoutHeaders)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) { phase.Store(TimeoutConnect) },
+		GotConn: func(httptrace.GotConnInfo) { phase.Store(TimeoutHeaders) },
+	})
+	timeoutErr := func(err error, p TimeoutPhase) error {
+		if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		return &TimeoutError{Operation: op, Phase: p, Limit: deadline.Sub(start), Err: err}
+	}
+
+	rsp, err := next.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return rsp, timeoutErr(err, phase.Load().(TimeoutPhase))
+	}
+	rsp.Body = &timeoutBody{ReadCloser: rsp.Body, cancel: cancel, wrap: timeoutErr}
+	return rsp, nil
+}
+
+// timeoutBody keeps the call's deadline running until the body is closed.
+type timeoutBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	wrap   func(error, TimeoutPhase) error
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		return n, err
+	}
+	return n, b.wrap(err, TimeoutBody)
+}
+
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
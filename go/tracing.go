@@ -0,0 +1,110 @@
+package client
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/nus25/gyoka-client/go"
+
+// Span attributes set by WithTracing in addition to the OpenTelemetry HTTP
+// attributes.
+const (
+	AttrOperation    = attribute.Key("gyoka.operation")
+	AttrFeed         = attribute.Key("gyoka.feed")
+	AttrBatchSize    = attribute.Key("gyoka.batch.size")
+	AttrErrorCode    = attribute.Key("gyoka.error.code")
+	AttrItemResults  = attribute.Key("gyoka.batch.items")
+	AttrItemFailures = attribute.Key("gyoka.batch.failures")
+)
+
+// TracingOptions configures WithTracing.
+type TracingOptions struct {
+	// TracerProvider defaults to the global provider, which is a no-op until
+	// otel.SetTracerProvider is called.
+	TracerProvider trace.TracerProvider
+	// Propagator defaults to W3C trace context.
+	Propagator propagation.TextMapPropagator
+}
+
+// WithTracing wraps the client's HttpRequestDoer so every call emits a client
+// span named after the operation, e.g. "postAddPost", and propagates the
+// trace context in the request headers. It must come after WithHTTPClient.
+//
+// Request and response bodies are only inspected for attributes when the
+// span is recording.
+func WithTracing(opts TracingOptions) ClientOption {
+	return func(c *Client) error {
+		tp := opts.TracerProvider
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		prop := opts.Propagator
+		if prop == nil {
+			prop = propagation.TraceContext{}
+		}
+		tracer := tp.Tracer(tracerName)
+		wrapDoer(c, func(next HttpRequestDoer) HttpRequestDoer {
+			return &tracingDoer{next: next, tracer: tracer, propagator: prop}
+		})
+		return nil
+	}
+}
+
+type tracingDoer struct {
+	next       HttpRequestDoer
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (d *tracingDoer) Do(req *http.Request) (*http.Response, error) {
+	op := operationName(req)
+	ctx, span := d.tracer.Start(req.Context(), op, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	d.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	recording := span.IsRecording()
+	if recording {
+		info := inspectRequest(req)
+		span.SetAttributes(
+			AttrOperation.String(op),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+		)
+		if info.Feed != "" {
+			span.SetAttributes(AttrFeed.String(info.Feed))
+		}
+		if info.Operation == OpPostBatchAddPosts || info.Operation == OpPostBatchRemovePosts {
+			span.SetAttributes(AttrBatchSize.Int(info.BatchSize))
+		}
+	}
+
+	rsp, err := d.next.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return rsp, err
+	}
+	if !recording {
+		return rsp, nil
+	}
+	info := inspectResponseHead(op, rsp)
+	span.SetAttributes(attribute.Int("http.response.status_code", info.StatusCode))
+	if info.ErrorCode != "" {
+		span.SetAttributes(AttrErrorCode.String(info.ErrorCode))
+	}
+	if info.Items > 0 {
+		span.SetAttributes(AttrItemResults.Int(info.Items), AttrItemFailures.Int(info.ItemFailures))
+	}
+	if info.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(info.StatusCode))
+	}
+	return rsp, nil
+}
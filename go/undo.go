@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrAlreadyUndone is returned by Undo for an entry that was restored
+// before.
+var ErrAlreadyUndone = errors.New("journal entry already undone")
+
+// JournalEntry is the snapshot taken before one removePostByAuthor or
+// trimFeed call: the call's parameters and every post it was going to
+// remove, with all fields needed to add them back.
+type JournalEntry struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Feed      string    `json:"feed"`
+	// Author is the author of a removePostByAuthor call.
+	Author string `json:"author,omitempty"`
+	// Remain is the remain of a trimFeed call.
+	Remain   *int       `json:"remain,omitempty"`
+	Posts    []Post     `json:"posts"`
+	UndoneAt *time.Time `json:"undoneAt,omitempty"`
+}
+
+// UndoJournal keeps JournalEntry files in a directory, one JSON file per
+// entry named after its ID.
+type UndoJournal struct {
+	dir string
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewUndoJournal uses dir as journal, creating it if needed.
+func NewUndoJournal(dir string) (*UndoJournal, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &UndoJournal{dir: dir}, nil
+}
+
+func (j *UndoJournal) now() time.Time {
+	if j.Now != nil {
+		return j.Now()
+	}
+	return time.Now()
+}
+
+func (j *UndoJournal) path(id string) string {
+	return filepath.Join(j.dir, id+".json")
+}
+
+// record assigns e an ID and time and saves it.
+func (j *UndoJournal) record(e *JournalEntry) error {
+	var suffix [4]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return err
+	}
+	e.Time = j.now().UTC()
+	e.ID = e.Time.Format("20060102T150405.000000Z") + "-" + hex.EncodeToString(suffix[:])
+	return j.save(e)
+}
+
+func (j *UndoJournal) save(e *JournalEntry) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := j.path(e.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path(e.ID))
+}
+
+// discard deletes the entry of a call that did not remove anything.
+func (j *UndoJournal) discard(id string) error {
+	return os.Remove(j.path(id))
+}
+
+// Get returns the entry with id.
+func (j *UndoJournal) Get(id string) (*JournalEntry, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid journal id %q", id)
+	}
+	data, err := os.ReadFile(j.path(id))
+	if err != nil {
+		return nil, err
+	}
+	var e JournalEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("read journal entry %s: %w", id, err)
+	}
+	return &e, nil
+}
+
+// List returns all entries, oldest first.
+func (j *UndoJournal) List() ([]*JournalEntry, error) {
+	names, err := filepath.Glob(filepath.Join(j.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	entries := make([]*JournalEntry, 0, len(names))
+	for _, name := range names {
+		e, err := j.Get(strings.TrimSuffix(filepath.Base(name), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// UndoResult is the outcome of Undo.
+type UndoResult struct {
+	Entry    *JournalEntry     `json:"entry"`
+	Restored int               `json:"restored"`
+	Failures []BatchItemResult `json:"failures,omitempty"`
+}
+
+// Undo adds the posts of the entry with id back to its feed with
+// batchAddPosts, keeping their cid, indexedAt, languages, feedContext and
+// reason. The entry is marked undone once every post was restored, so a
+// partial undo can be retried.
+func (j *UndoJournal) Undo(ctx context.Context, c ClientWithResponsesInterface, id string) (*UndoResult, error) {
+	e, err := j.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.UndoneAt != nil {
+		return nil, fmt.Errorf("%s: %w at %s", id, ErrAlreadyUndone, e.UndoneAt.Format(time.RFC3339))
+	}
+	result := &UndoResult{Entry: e}
+	items, err := batcherOr(nil, 0).AddPosts(ctx, c, e.Feed, e.Posts)
+	result.Restored, result.Failures = countOK(items)
+	if err != nil {
+		return result, err
+	}
+	if len(result.Failures) > 0 {
+		return result, nil
+	}
+	undoneAt := j.now().UTC()
+	e.UndoneAt = &undoneAt
+	return result, j.save(e)
+}
@@ -12,6 +12,7 @@ NC='\033[0m' # No Color
 GITHUB_RAW_URL="https://raw.githubusercontent.com/nus25/gyoka/main/packages/editor/schema/openapi.json"
 SCHEMA_FILE="schema/openapi.json"
 BACKUP_FILE="schema/openapi.json.backup"
+ACK_FILE="schema/acknowledged-changes.txt"
 
 # Get the script directory
 SCRIPT_DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" && pwd )"
@@ -23,6 +24,11 @@ if ! command -v jq >/dev/null 2>&1; then
     echo "Please install jq to validate the downloaded schema."
     exit 1
 fi
+if ! command -v go >/dev/null 2>&1; then
+    echo -e "${RED}✗ Missing dependency: go${NC}"
+    echo "Please install Go to check the downloaded schema for breaking changes."
+    exit 1
+fi
 
 # Temporary file for atomic updates
 TEMP_SCHEMA_FILE="$(mktemp 2>/dev/null)"
@@ -53,6 +59,25 @@ if curl -f -s -o "$TEMP_SCHEMA_FILE" "$GITHUB_RAW_URL"; then
     echo -e "${YELLOW}Validating JSON format...${NC}"
     if jq empty "$TEMP_SCHEMA_FILE" 2>/dev/null; then
         echo -e "${GREEN}✓ JSON validation passed${NC}"
+
+        # Classify changes against the current schema
+        if [ -f "$SCHEMA_FILE" ]; then
+            echo -e "${YELLOW}Checking for breaking changes...${NC}"
+            ACK_ARGS=()
+            if [ -f "$ACK_FILE" ]; then
+                ACK_ARGS=(-ack "$SCRIPT_DIR/$ACK_FILE")
+            fi
+            if ! (cd go/generate && go run ./schemacheck diff -old "$SCRIPT_DIR/$SCHEMA_FILE" -new "$TEMP_SCHEMA_FILE" "${ACK_ARGS[@]}"); then
+                echo -e "${RED}✗ Breaking schema changes found${NC}"
+                echo "Add the locations to $ACK_FILE to accept them."
+                if [ -f "$BACKUP_FILE" ]; then
+                    rm "$BACKUP_FILE"
+                fi
+                exit 1
+            fi
+            echo -e "${GREEN}✓ Schema check passed${NC}"
+        fi
+
         mv "$TEMP_SCHEMA_FILE" "$SCHEMA_FILE"
         
         # Remove backup if download was successful
@@ -63,6 +88,7 @@ if curl -f -s -o "$TEMP_SCHEMA_FILE" "$GITHUB_RAW_URL"; then
         
         echo ""
         echo -e "${GREEN}Schema update completed successfully!${NC}"
+        echo "Regenerate the client with: cd ./go/generate/ && go generate"
     else
         echo -e "${RED}✗ Invalid JSON format${NC}"
         